@@ -0,0 +1,145 @@
+package cryptowatch
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// ExchangesByMarketCount returns all exchanges that list markets ranked by their
+// number of active markets, in descending order. Counts are derived from every
+// page of AllMarkets.
+func (c *Client) ExchangesByMarketCount() ([]ExchangeRank, error) {
+	markets, err := c.AllMarkets(context.Background())
+
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, market := range markets {
+		count := counts[market.Exchange]
+		if market.Active {
+			count++
+		}
+		counts[market.Exchange] = count
+	}
+
+	ranks := make([]ExchangeRank, 0, len(counts))
+	for exchange, count := range counts {
+		ranks = append(ranks, ExchangeRank{Exchange: exchange, ActiveMarkets: count})
+	}
+
+	sort.Slice(ranks, func(i, j int) bool {
+		if ranks[i].ActiveMarkets != ranks[j].ActiveMarkets {
+			return ranks[i].ActiveMarkets > ranks[j].ActiveMarkets
+		}
+		return ranks[i].Exchange < ranks[j].Exchange
+	})
+
+	return ranks, nil
+}
+
+// DiffActive compares the markets against a previous listing and reports the
+// markets whose active flag changed. Markets that are new in this listing count
+// as activated when active, and markets that were dropped from it count as
+// deactivated when they were previously active.
+func (markets GeneralMarkets) DiffActive(previous []GeneralMarket) (nowActive, nowInactive []MarketRef) {
+	before := make(map[MarketRef]bool, len(previous))
+	for _, market := range previous {
+		before[MarketRef{market.Exchange, market.Pair}] = market.Active
+	}
+
+	current := make(map[MarketRef]bool, len(markets))
+	for _, market := range markets {
+		ref := MarketRef{market.Exchange, market.Pair}
+		current[ref] = market.Active
+		wasActive, existed := before[ref]
+
+		switch {
+		case market.Active && (!existed || !wasActive):
+			nowActive = append(nowActive, ref)
+		case !market.Active && existed && wasActive:
+			nowInactive = append(nowInactive, ref)
+		}
+	}
+
+	for _, market := range previous {
+		ref := MarketRef{market.Exchange, market.Pair}
+		if _, ok := current[ref]; !ok && market.Active {
+			nowInactive = append(nowInactive, ref)
+		}
+	}
+
+	return nowActive, nowInactive
+}
+
+// ExchangePairCounts returns the number of distinct pairs listed on each exchange,
+// active or not. Counts are derived from every page of AllMarkets.
+func (c *Client) ExchangePairCounts() (map[string]int, error) {
+	markets, err := c.AllMarkets(context.Background())
+
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[MarketRef]bool, len(markets))
+	counts := make(map[string]int)
+	for _, market := range markets {
+		ref := MarketRef{market.Exchange, market.Pair}
+		if !seen[ref] {
+			seen[ref] = true
+			counts[market.Exchange]++
+		}
+	}
+
+	return counts, nil
+}
+
+// MarketsFiltered returns the markets matching every field set in filter. The
+// exchange filter is applied to the markets listing directly, while the base and
+// quote filters also need the pairs listing, which is fetched once and cached
+// for the life of the client. Symbols match in any casing.
+func (c *Client) MarketsFiltered(ctx context.Context, filter MarketFilter) ([]GeneralMarket, error) {
+	markets, err := c.AllMarkets(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var pairs map[string]Pair
+	if filter.Base != "" || filter.Quote != "" {
+		listed, err := c.cachedPairs(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		pairs = make(map[string]Pair, len(listed))
+		for _, pair := range listed {
+			pairs[pair.Symbol] = pair
+		}
+	}
+
+	filtered := []GeneralMarket{}
+	for _, market := range markets {
+		if filter.ActiveOnly && !market.Active {
+			continue
+		}
+		if filter.Exchange != "" && !strings.EqualFold(market.Exchange, filter.Exchange) {
+			continue
+		}
+		if pairs != nil {
+			pair := pairs[market.Pair]
+			if filter.Base != "" && !strings.EqualFold(pair.Base.Symbol, filter.Base) {
+				continue
+			}
+			if filter.Quote != "" && !strings.EqualFold(pair.Quote.Symbol, filter.Quote) {
+				continue
+			}
+		}
+		filtered = append(filtered, market)
+	}
+
+	return filtered, nil
+}
@@ -0,0 +1,149 @@
+package cryptowatch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// serveMarketPages serves the markets listing split across pages, each page's
+// cursor being the index of the page after it.
+func serveMarketPages(t *testing.T, pages ...string) {
+	serve(t, func(w http.ResponseWriter, r *http.Request) {
+		i, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+		fmt.Fprintf(w, `{"result":%s,"cursor":{"last":"%d","hasMore":%t}}`, pages[i], i+1, i+1 < len(pages))
+	})
+}
+
+func TestExchangesByMarketCount(t *testing.T) {
+	serveMarketPages(t, `[
+			{"exchange":"kraken","pair":"btcusd","active":true},
+			{"exchange":"kraken","pair":"ethusd","active":true},
+			{"exchange":"kraken","pair":"ltcusd","active":false},
+			{"exchange":"gdax","pair":"btcusd","active":true}
+		]`, `[
+			{"exchange":"bitfinex","pair":"btcusd","active":true},
+			{"exchange":"bitfinex","pair":"ethusd","active":true},
+			{"exchange":"bitfinex","pair":"ltcusd","active":true},
+			{"exchange":"quoine","pair":"btcjpy","active":false}
+		]`)
+
+	ranks, err := ExchangesByMarketCount()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ExchangeRank{
+		{Exchange: "bitfinex", ActiveMarkets: 3},
+		{Exchange: "kraken", ActiveMarkets: 2},
+		{Exchange: "gdax", ActiveMarkets: 1},
+		{Exchange: "quoine", ActiveMarkets: 0},
+	}
+	if !reflect.DeepEqual(ranks, expected) {
+		t.Errorf("got %+v, want %+v", ranks, expected)
+	}
+}
+
+func TestDiffActive(t *testing.T) {
+	previous := []GeneralMarket{
+		{Exchange: "kraken", Pair: "btcusd", Active: true},
+		{Exchange: "kraken", Pair: "ethusd", Active: false},
+		{Exchange: "gdax", Pair: "btcusd", Active: true},
+		{Exchange: "gdax", Pair: "ltcusd", Active: true},
+		{Exchange: "gdax", Pair: "etcusd", Active: false},
+	}
+	current := GeneralMarkets{
+		{Exchange: "kraken", Pair: "btcusd", Active: true},
+		{Exchange: "kraken", Pair: "ethusd", Active: true},
+		{Exchange: "gdax", Pair: "btcusd", Active: false},
+		{Exchange: "bitfinex", Pair: "btcusd", Active: true},
+		{Exchange: "bitfinex", Pair: "ethusd", Active: false},
+	}
+
+	nowActive, nowInactive := current.DiffActive(previous)
+
+	expectedActive := []MarketRef{{"kraken", "ethusd"}, {"bitfinex", "btcusd"}}
+	if !reflect.DeepEqual(nowActive, expectedActive) {
+		t.Errorf("nowActive = %v, want %v", nowActive, expectedActive)
+	}
+
+	expectedInactive := []MarketRef{{"gdax", "btcusd"}, {"gdax", "ltcusd"}}
+	if !reflect.DeepEqual(nowInactive, expectedInactive) {
+		t.Errorf("nowInactive = %v, want %v", nowInactive, expectedInactive)
+	}
+
+	nowActive, nowInactive = current.DiffActive(current)
+	if nowActive != nil || nowInactive != nil {
+		t.Errorf("identical listings reported changes: %v, %v", nowActive, nowInactive)
+	}
+}
+
+func TestExchangePairCounts(t *testing.T) {
+	serveMarketPages(t, `[
+			{"exchange":"kraken","pair":"btcusd","active":true},
+			{"exchange":"kraken","pair":"btcusd","active":true}
+		]`, `[
+			{"exchange":"kraken","pair":"ethusd","active":false},
+			{"exchange":"gdax","pair":"btcusd","active":true},
+			{"exchange":"gdax","pair":"btcusd","active":false}
+		]`)
+
+	counts, err := ExchangePairCounts()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]int{"kraken": 2, "gdax": 1}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("got %v, want %v", counts, expected)
+	}
+}
+
+func TestMarketsFiltered(t *testing.T) {
+	serveResults(t, map[string]string{
+		"/markets": `[
+			{"exchange":"binance","pair":"ethbtc","active":true},
+			{"exchange":"binance","pair":"ethusdt","active":true},
+			{"exchange":"binance","pair":"etheur","active":false},
+			{"exchange":"binance","pair":"btcusdt","active":true},
+			{"exchange":"kraken","pair":"ethbtc","active":true}
+		]`,
+		"/pairs": `[
+			{"symbol":"ethbtc","base":{"symbol":"eth"},"quote":{"symbol":"btc"}},
+			{"symbol":"ethusdt","base":{"symbol":"eth"},"quote":{"symbol":"usdt"}},
+			{"symbol":"etheur","base":{"symbol":"eth"},"quote":{"symbol":"eur"}},
+			{"symbol":"btcusdt","base":{"symbol":"btc"},"quote":{"symbol":"usdt"}}
+		]`,
+	})
+
+	cases := []struct {
+		filter   MarketFilter
+		expected []string
+	}{
+		{MarketFilter{Exchange: "Binance", Base: "eth"}, []string{"binance:ethbtc", "binance:ethusdt", "binance:etheur"}},
+		{MarketFilter{Exchange: "binance", Base: "eth", ActiveOnly: true}, []string{"binance:ethbtc", "binance:ethusdt"}},
+		{MarketFilter{Quote: "usdt"}, []string{"binance:ethusdt", "binance:btcusdt"}},
+		{MarketFilter{Base: "eth", Quote: "btc"}, []string{"binance:ethbtc", "kraken:ethbtc"}},
+		{MarketFilter{Exchange: "bitstamp"}, []string{}},
+	}
+	for _, c := range cases {
+		markets, err := MarketsFiltered(context.Background(), c.filter)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := []string{}
+		for _, market := range markets {
+			got = append(got, market.Exchange+":"+market.Pair)
+		}
+		if !reflect.DeepEqual(got, c.expected) {
+			t.Errorf("MarketsFiltered(%+v) = %v, want %v", c.filter, got, c.expected)
+		}
+	}
+}
@@ -0,0 +1,336 @@
+package cryptowatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// batchConcurrency is the default bound on the number of requests a batch
+// helper has in flight.
+const batchConcurrency = 8
+
+// BatchError reports the markets whose requests failed within a batch. Batch
+// helpers return it alongside the results of the markets that succeeded.
+type BatchError map[MarketRef]error
+
+func (e BatchError) Error() string {
+	messages := make([]string, 0, len(e))
+	for ref, err := range e {
+		messages = append(messages, fmt.Sprintf("%s:%s: %v", ref.Exchange, ref.Pair, err))
+	}
+	sort.Strings(messages)
+	return fmt.Sprintf("%d market requests failed: %s", len(e), strings.Join(messages, "; "))
+}
+
+// forEachMarket calls fn for every market, at most the Client's concurrency at a
+// time, and collects the failures. Markets not yet started when ctx is done fail
+// with ctx.Err().
+func (c *Client) forEachMarket(ctx context.Context, refs []MarketRef, fn func(ref MarketRef) error) error {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		failures = make(BatchError)
+		slots    = make(chan struct{}, c.concurrency)
+	)
+
+	fail := func(ref MarketRef, err error) {
+		mu.Lock()
+		failures[ref] = err
+		mu.Unlock()
+	}
+
+	for _, ref := range refs {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			fail(ref, ctx.Err())
+			continue
+		}
+
+		wg.Add(1)
+		go func(ref MarketRef) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			if err := ctx.Err(); err != nil {
+				fail(ref, err)
+			} else if err := fn(ref); err != nil {
+				fail(ref, err)
+			}
+		}(ref)
+	}
+	wg.Wait()
+
+	if len(failures) > 0 {
+		return failures
+	}
+	return nil
+}
+
+// pairMarketRefs resolves the active markets trading pair.
+func (c *Client) pairMarketRefs(ctx context.Context, pair string) ([]MarketRef, error) {
+	markets, err := c.PairMarketsContext(ctx, pair)
+
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]MarketRef, 0, len(markets.Markets))
+	for _, market := range markets.Markets {
+		if market.Active {
+			refs = append(refs, MarketRef{market.Exchange, market.Pair})
+		}
+	}
+	return refs, nil
+}
+
+// WideSpreadMarkets fetches the order book of every active market trading pair
+// and returns those whose spread exceeds thresholdBps basis points of the mid,
+// widest first. Markets that fail to load are skipped and reported in a
+// BatchError returned alongside the results.
+func (c *Client) WideSpreadMarkets(ctx context.Context, pair string, thresholdBps float64) ([]MarketSpread, error) {
+	refs, err := c.pairMarketRefs(ctx, pair)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu      sync.Mutex
+		spreads []MarketSpread
+	)
+
+	err = c.forEachMarket(ctx, refs, func(ref MarketRef) error {
+		book, err := c.OrderBookWithOptionsContext(ctx, ref.Exchange, ref.Pair, OrderBookOptions{Limit: 1})
+
+		if err != nil {
+			return err
+		}
+		bid, ask, ok := book.topOfBook()
+
+		if !ok {
+			return nil
+		}
+
+		spread := MarketSpread{Market: ref, Bid: bid, Ask: ask, SpreadBps: (ask - bid) / ((ask + bid) / 2) * 1e4}
+
+		if spread.SpreadBps > thresholdBps {
+			mu.Lock()
+			spreads = append(spreads, spread)
+			mu.Unlock()
+		}
+		return nil
+	})
+
+	sort.Slice(spreads, func(i, j int) bool {
+		return spreads[i].SpreadBps > spreads[j].SpreadBps
+	})
+
+	return spreads, err
+}
+
+// AssetMarketPrices resolves every active market that has symbol as its base or
+// quote and fetches their prices concurrently. Markets whose price fails to load
+// are left out and reported in a BatchError returned alongside the prices.
+func (c *Client) AssetMarketPrices(ctx context.Context, symbol string) (map[MarketRef]float64, error) {
+	asset, err := c.AssetMarketsContext(ctx, symbol)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []MarketRef
+	for _, markets := range [][]AssetMarket{asset.Markets.Base, asset.Markets.Quote} {
+		for _, market := range markets {
+			if market.Active {
+				refs = append(refs, MarketRef{market.Exchange, market.Pair})
+			}
+		}
+	}
+
+	var mu sync.Mutex
+	prices := make(map[MarketRef]float64, len(refs))
+
+	err = c.forEachMarket(ctx, refs, func(ref MarketRef) error {
+		price, err := c.MarketPriceContext(ctx, ref.Exchange, ref.Pair)
+
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		prices[ref] = price
+		mu.Unlock()
+		return nil
+	})
+
+	return prices, err
+}
+
+// ScanCrossedBooks fetches the order books of refs concurrently and returns, in
+// the order given, the markets whose best bid is at or above their best ask.
+// Markets that fail to load are reported in a BatchError returned alongside the
+// crossed markets.
+func (c *Client) ScanCrossedBooks(ctx context.Context, refs []MarketRef) ([]MarketRef, error) {
+	var mu sync.Mutex
+	crossed := make(map[MarketRef]bool)
+
+	err := c.forEachMarket(ctx, refs, func(ref MarketRef) error {
+		book, err := c.OrderBookWithOptionsContext(ctx, ref.Exchange, ref.Pair, OrderBookOptions{Limit: 1})
+
+		if err != nil {
+			return err
+		}
+
+		if bid, ask, ok := book.topOfBook(); ok && bid >= ask {
+			mu.Lock()
+			crossed[ref] = true
+			mu.Unlock()
+		}
+		return nil
+	})
+
+	var markets []MarketRef
+	for _, ref := range refs {
+		if crossed[ref] {
+			markets = append(markets, ref)
+			delete(crossed, ref)
+		}
+	}
+
+	return markets, err
+}
+
+// MarketsByLiquidity fetches the order book of every active market trading pair
+// and ranks the markets by the combined amount resting within depthPct of their
+// mid, most liquid first. depthPct is a fraction as for LiquidityWithin. Markets
+// that fail to load are left out and reported in a BatchError returned alongside
+// the ranking.
+func (c *Client) MarketsByLiquidity(ctx context.Context, pair string, depthPct float64) ([]MarketLiquidity, error) {
+	if depthPct <= 0 {
+		return nil, errors.New("depthPct must be positive")
+	}
+
+	refs, err := c.pairMarketRefs(ctx, pair)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu        sync.Mutex
+		liquidity []MarketLiquidity
+	)
+
+	err = c.forEachMarket(ctx, refs, func(ref MarketRef) error {
+		book, err := c.OrderBookContext(ctx, ref.Exchange, ref.Pair)
+
+		if err != nil {
+			return err
+		}
+
+		bidVol, askVol := book.LiquidityWithin(depthPct)
+
+		mu.Lock()
+		liquidity = append(liquidity, MarketLiquidity{Market: ref, BidVolume: bidVol, AskVolume: askVol})
+		mu.Unlock()
+		return nil
+	})
+
+	sort.Slice(liquidity, func(i, j int) bool {
+		return liquidity[i].Total() > liquidity[j].Total()
+	})
+
+	return liquidity, err
+}
+
+// PriceOutliers fetches the price of every active market trading pair and
+// returns the markets whose price deviates from the cross-market median by more
+// than thresholdPct, a fraction, largest deviation first. Markets that fail to
+// load are left out of the median and reported in a BatchError returned
+// alongside the outliers.
+func (c *Client) PriceOutliers(ctx context.Context, pair string, thresholdPct float64) ([]PriceOutlier, error) {
+	refs, err := c.pairMarketRefs(ctx, pair)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	prices := make(map[MarketRef]float64, len(refs))
+
+	err = c.forEachMarket(ctx, refs, func(ref MarketRef) error {
+		price, err := c.MarketPriceContext(ctx, ref.Exchange, ref.Pair)
+
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		prices[ref] = price
+		mu.Unlock()
+		return nil
+	})
+
+	if len(prices) == 0 {
+		return nil, err
+	}
+
+	sorted := make([]float64, 0, len(prices))
+	for _, price := range prices {
+		sorted = append(sorted, price)
+	}
+	sort.Float64s(sorted)
+
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + median) / 2
+	}
+
+	if median == 0 {
+		return nil, err
+	}
+
+	var outliers []PriceOutlier
+	for ref, price := range prices {
+		if deviation := (price - median) / median; math.Abs(deviation) > thresholdPct {
+			outliers = append(outliers, PriceOutlier{Market: ref, Price: price, Median: median, Deviation: deviation})
+		}
+	}
+
+	sort.Slice(outliers, func(i, j int) bool {
+		return math.Abs(outliers[i].Deviation) > math.Abs(outliers[j].Deviation)
+	})
+
+	return outliers, err
+}
+
+// Summaries fetches the 24-hour summary of each market concurrently, at most the
+// Client's concurrency at a time (see WithConcurrency), and through its rate
+// limiter if it has one. Markets whose requests fail are left out of the map
+// and reported in a BatchError returned alongside it.
+func (c *Client) Summaries(ctx context.Context, markets []MarketRef) (map[MarketRef]Summary, error) {
+	var mu sync.Mutex
+	summaries := make(map[MarketRef]Summary, len(markets))
+
+	err := c.forEachMarket(ctx, markets, func(ref MarketRef) error {
+		summary, err := c.MarketSummaryContext(ctx, ref.Exchange, ref.Pair)
+
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		summaries[ref] = summary
+		mu.Unlock()
+		return nil
+	})
+	return summaries, err
+}
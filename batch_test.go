@@ -0,0 +1,208 @@
+package cryptowatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWideSpreadMarkets(t *testing.T) {
+	serveResults(t, map[string]string{
+		"/pairs/btcusd": `{"symbol":"btcusd","markets":[
+			{"exchange":"kraken","pair":"btcusd","active":true},
+			{"exchange":"gdax","pair":"btcusd","active":true},
+			{"exchange":"bitstamp","pair":"btcusd","active":true},
+			{"exchange":"quoine","pair":"btcusd","active":false}
+		]}`,
+		"/markets/kraken/btcusd/orderbook": `{"asks":[[10100,1]],"bids":[[9900,1]]}`,
+		"/markets/gdax/btcusd/orderbook":   `{"asks":[[10001,1]],"bids":[[9999,1]]}`,
+	})
+
+	spreads, err := WideSpreadMarkets(context.Background(), "btcusd", 50)
+
+	var batchErr BatchError
+	if !errors.As(err, &batchErr) || len(batchErr) != 1 || batchErr[MarketRef{"bitstamp", "btcusd"}] == nil {
+		t.Errorf("err = %v, want a BatchError for bitstamp only", err)
+	}
+
+	if len(spreads) != 1 || spreads[0].Market != (MarketRef{"kraken", "btcusd"}) {
+		t.Fatalf("spreads = %+v, want kraken only", spreads)
+	}
+	if math.Abs(spreads[0].SpreadBps-200) > 1e-9 {
+		t.Errorf("SpreadBps = %v, want 200", spreads[0].SpreadBps)
+	}
+}
+
+func TestAssetMarketPrices(t *testing.T) {
+	serveResults(t, map[string]string{
+		"/assets/eth": `{"symbol":"eth","markets":{
+			"base":[
+				{"exchange":"kraken","pair":"ethusd","active":true},
+				{"exchange":"gdax","pair":"ethusd","active":true},
+				{"exchange":"quoine","pair":"ethjpy","active":false}
+			],
+			"quote":[
+				{"exchange":"kraken","pair":"etceth","active":true}
+			]
+		}}`,
+		"/markets/kraken/ethusd/price": `{"price":301.5}`,
+		"/markets/kraken/etceth/price": `{"price":0.05}`,
+	})
+
+	prices, err := AssetMarketPrices(context.Background(), "eth")
+
+	var batchErr BatchError
+	if !errors.As(err, &batchErr) || len(batchErr) != 1 || batchErr[MarketRef{"gdax", "ethusd"}] == nil {
+		t.Errorf("err = %v, want a BatchError for gdax only", err)
+	}
+
+	expected := map[MarketRef]float64{
+		{"kraken", "ethusd"}: 301.5,
+		{"kraken", "etceth"}: 0.05,
+	}
+	if !reflect.DeepEqual(prices, expected) {
+		t.Errorf("prices = %v, want %v", prices, expected)
+	}
+}
+
+func TestScanCrossedBooks(t *testing.T) {
+	serveResults(t, map[string]string{
+		"/markets/kraken/btcusd/orderbook":   `{"asks":[[101,1]],"bids":[[100,1]]}`,
+		"/markets/gdax/btcusd/orderbook":     `{"asks":[[100,1]],"bids":[[100.5,1]]}`,
+		"/markets/bitstamp/btcusd/orderbook": `{"asks":[[100,1]],"bids":[[100,1]]}`,
+		"/markets/bitfinex/btcusd/orderbook": `{"asks":[],"bids":[[100,1]]}`,
+	})
+
+	refs := []MarketRef{
+		{"kraken", "btcusd"},
+		{"gdax", "btcusd"},
+		{"quoine", "btcusd"},
+		{"bitstamp", "btcusd"},
+		{"bitfinex", "btcusd"},
+	}
+	crossed, err := ScanCrossedBooks(context.Background(), refs)
+
+	var batchErr BatchError
+	if !errors.As(err, &batchErr) || len(batchErr) != 1 || batchErr[MarketRef{"quoine", "btcusd"}] == nil {
+		t.Errorf("err = %v, want a BatchError for quoine only", err)
+	}
+
+	expected := []MarketRef{{"gdax", "btcusd"}, {"bitstamp", "btcusd"}}
+	if !reflect.DeepEqual(crossed, expected) {
+		t.Errorf("crossed = %v, want %v", crossed, expected)
+	}
+}
+
+func TestMarketsByLiquidity(t *testing.T) {
+	serveResults(t, map[string]string{
+		"/pairs/btcusd": `{"symbol":"btcusd","markets":[
+			{"exchange":"kraken","pair":"btcusd","active":true},
+			{"exchange":"gdax","pair":"btcusd","active":true},
+			{"exchange":"bitstamp","pair":"btcusd","active":true}
+		]}`,
+		"/markets/kraken/btcusd/orderbook":   `{"asks":[[101,1],[110,50]],"bids":[[99,2],[90,50]]}`,
+		"/markets/gdax/btcusd/orderbook":     `{"asks":[[101,4],[101.5,4]],"bids":[[99,5]]}`,
+		"/markets/bitstamp/btcusd/orderbook": `{"asks":[[101,1]],"bids":[[99,1]]}`,
+	})
+
+	ranking, err := MarketsByLiquidity(context.Background(), "btcusd", 0.02)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []MarketLiquidity{
+		{Market: MarketRef{"gdax", "btcusd"}, BidVolume: 5, AskVolume: 8},
+		{Market: MarketRef{"kraken", "btcusd"}, BidVolume: 2, AskVolume: 1},
+		{Market: MarketRef{"bitstamp", "btcusd"}, BidVolume: 1, AskVolume: 1},
+	}
+	if !reflect.DeepEqual(ranking, expected) {
+		t.Errorf("ranking = %+v, want %+v", ranking, expected)
+	}
+
+	if _, err := MarketsByLiquidity(context.Background(), "btcusd", 0); err == nil {
+		t.Error("expected an error for a non-positive depthPct")
+	}
+}
+
+func TestPriceOutliers(t *testing.T) {
+	serveResults(t, map[string]string{
+		"/pairs/btcusd": `{"symbol":"btcusd","markets":[
+			{"exchange":"kraken","pair":"btcusd","active":true},
+			{"exchange":"gdax","pair":"btcusd","active":true},
+			{"exchange":"bitstamp","pair":"btcusd","active":true},
+			{"exchange":"bitfinex","pair":"btcusd","active":true},
+			{"exchange":"quoine","pair":"btcusd","active":true}
+		]}`,
+		"/markets/kraken/btcusd/price":   `{"price":10000}`,
+		"/markets/gdax/btcusd/price":     `{"price":10010}`,
+		"/markets/bitstamp/btcusd/price": `{"price":9990}`,
+		"/markets/bitfinex/btcusd/price": `{"price":10600}`,
+		"/markets/quoine/btcusd/price":   `{"price":10020}`,
+	})
+
+	outliers, err := PriceOutliers(context.Background(), "btcusd", 0.02)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(outliers) != 1 {
+		t.Fatalf("outliers = %+v, want bitfinex only", outliers)
+	}
+	outlier := outliers[0]
+	if outlier.Market != (MarketRef{"bitfinex", "btcusd"}) || outlier.Median != 10010 || math.Abs(outlier.Deviation-590.0/10010) > 1e-9 {
+		t.Errorf("outlier = %+v", outlier)
+	}
+}
+
+func TestSummaries(t *testing.T) {
+	var (
+		mu                sync.Mutex
+		inFlight, maxSeen int
+	)
+	url := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxSeen {
+			maxSeen = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		if r.URL.Path == "/markets/mtgox/btcusd/summary" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":"Route not found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"result":{"price":{"last":100},"volume":5}}`)
+	})
+
+	markets := []MarketRef{
+		{"kraken", "btcusd"}, {"gdax", "btcusd"}, {"bitstamp", "btcusd"},
+		{"mtgox", "btcusd"}, {"kraken", "ethusd"}, {"gdax", "ethusd"},
+	}
+	summaries, err := NewClient(WithBaseURL(url), WithConcurrency(2)).Summaries(context.Background(), markets)
+
+	var batchErr BatchError
+	if !errors.As(err, &batchErr) || len(batchErr) != 1 || batchErr[MarketRef{"mtgox", "btcusd"}] == nil {
+		t.Errorf("err = %v, want a BatchError for mtgox only", err)
+	}
+	if len(summaries) != 5 || summaries[MarketRef{"gdax", "ethusd"}].Price.Last != 100 {
+		t.Errorf("summaries = %+v, want the 5 that succeeded", summaries)
+	}
+	if maxSeen > 2 {
+		t.Errorf("%d requests in flight, want at most 2", maxSeen)
+	}
+}
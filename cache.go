@@ -0,0 +1,184 @@
+package cryptowatch
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+)
+
+// responseCache keeps successful results by url until they expire.
+type responseCache struct {
+	ttl       time.Duration
+	endpoints map[string]time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	sweepAt int
+}
+
+// minCacheSweep is the fewest entries the cache holds before put sweeps out
+// expired ones.
+const minCacheSweep = 64
+
+// cacheEntry is a cached result and the cursor of the page after it.
+type cacheEntry struct {
+	result  json.RawMessage
+	next    string
+	expires time.Time
+}
+
+// noCacheKey marks a context whose requests bypass the cache.
+type noCacheKey struct{}
+
+// liveEndpoints change from one request to the next, so the WithCache default
+// does not apply to them.
+var liveEndpoints = map[string]bool{
+	"MarketPrice":     true,
+	"MarketSummary":   true,
+	"MarketTrades":    true,
+	"MarketOrderBook": true,
+}
+
+// WithCache caches successful results in memory for ttl, keyed by url, so
+// repeated calls within the window are answered without a request. Use
+// WithEndpointCacheTTL to give endpoints their own ttl, NoCache to bypass the
+// cache for a call and ClearCache to empty it. A ttl that is not positive only
+// caches the endpoints given their own ttl. The live endpoints, "MarketPrice",
+// "MarketSummary", "MarketTrades" and "MarketOrderBook", are not cached unless
+// given their own ttl.
+func WithCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.ensureCache().ttl = ttl
+	}
+}
+
+// WithEndpointCacheTTL caches the named endpoint for ttl instead of the
+// WithCache default, and not at all when ttl is not positive. The endpoints are
+// "Assets", "Asset" (AssetMarkets), "Pairs", "Pair" (PairMarkets), "Exchanges",
+// "Exchange", "ExchangeMarkets", "Markets", "Market", "MarketPrice",
+// "MarketSummary", "MarketTrades", "MarketOrderBook", "MarketOHLC",
+// "AggregratePrices" and "AggregrateSummaries".
+func WithEndpointCacheTTL(endpoint string, ttl time.Duration) Option {
+	return func(c *Client) {
+		c.ensureCache().endpoints[endpoint] = ttl
+	}
+}
+
+// NoCache returns a context whose requests bypass the Client's cache, neither
+// reading from it nor storing in it.
+func NoCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+// ClearCache empties the Client's cache.
+func (c *Client) ClearCache() {
+	if c.cache == nil {
+		return
+	}
+
+	c.cache.mu.Lock()
+	c.cache.entries = make(map[string]cacheEntry)
+	c.cache.mu.Unlock()
+}
+
+// ensureCache returns the Client's cache, creating it if need be.
+func (c *Client) ensureCache() *responseCache {
+	if c.cache == nil {
+		c.cache = &responseCache{
+			endpoints: make(map[string]time.Duration),
+			entries:   make(map[string]cacheEntry),
+		}
+	}
+	return c.cache
+}
+
+// ttlFor returns how long results from the endpoint at path, relative to the
+// base url, are cached.
+func (cache *responseCache) ttlFor(path string) time.Duration {
+	name := endpointName(path)
+	if ttl, ok := cache.endpoints[name]; ok {
+		return ttl
+	}
+	if liveEndpoints[name] {
+		return 0
+	}
+	return cache.ttl
+}
+
+// get returns the unexpired entry for url.
+func (cache *responseCache) get(url string) (cacheEntry, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	entry, ok := cache.entries[url]
+	if ok && time.Now().After(entry.expires) {
+		delete(cache.entries, url)
+		return cacheEntry{}, false
+	}
+	return entry, ok
+}
+
+// put stores an entry for url for ttl. Entries for urls that are never asked
+// for again, such as past pagination cursors, are only dropped by a sweep, so
+// put sweeps out the expired entries whenever the cache has doubled in size
+// since the last sweep.
+func (cache *responseCache) put(url string, entry cacheEntry, ttl time.Duration) {
+	now := time.Now()
+	entry.expires = now.Add(ttl)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.entries[url] = entry
+	if len(cache.entries) < cache.sweepAt {
+		return
+	}
+
+	for key, cached := range cache.entries {
+		if now.After(cached.expires) {
+			delete(cache.entries, key)
+		}
+	}
+	cache.sweepAt = 2 * len(cache.entries)
+	if cache.sweepAt < minCacheSweep {
+		cache.sweepAt = minCacheSweep
+	}
+}
+
+// endpointName returns the name of the index that path, relative to the base
+// url, was built from, with %v in an index standing for any one segment. When
+// several match, such as "markets/%v" and "markets/prices", the index with the
+// most literal segments wins.
+func endpointName(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	segments := strings.Split(path, "/")
+	best, bestLiterals := "", -1
+
+	for name, index := range indexes {
+		pattern := strings.Split(index, "/")
+
+		if len(pattern) != len(segments) {
+			continue
+		}
+
+		literals := 0
+		for i, part := range pattern {
+			if part == "%v" {
+				continue
+			}
+			if part != segments[i] {
+				literals = -1
+				break
+			}
+			literals++
+		}
+		if literals > bestLiterals {
+			best, bestLiterals = name, literals
+		}
+	}
+	return best
+}
@@ -0,0 +1,137 @@
+package cryptowatch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithCache(t *testing.T) {
+	requests := map[string]int{}
+	url := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/markets":
+			fmt.Fprint(w, `{"result":[{"exchange":"kraken","pair":"btcusd","active":true}]}`)
+		case "/assets":
+			fmt.Fprint(w, `{"result":[{"symbol":"btc"}]}`)
+		default:
+			fmt.Fprint(w, `{"result":{"price":42}}`)
+		}
+	})
+
+	c := NewClient(
+		WithBaseURL(url),
+		WithCache(time.Minute),
+		WithEndpointCacheTTL("MarketPrice", 0),
+		WithEndpointCacheTTL("Assets", 10*time.Millisecond),
+	)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if markets, err := c.MarketsContext(ctx); err != nil || len(markets) != 1 {
+			t.Fatalf("got %v, %v", markets, err)
+		}
+		if _, err := c.MarketPriceContext(ctx, "kraken", "btcusd"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if requests["/markets"] != 1 || requests["/markets/kraken/btcusd/price"] != 3 {
+		t.Errorf("requests = %v, want markets cached and prices not", requests)
+	}
+
+	if _, err := c.MarketsContext(NoCache(ctx)); err != nil {
+		t.Fatal(err)
+	}
+	c.ClearCache()
+	if _, err := c.MarketsContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if requests["/markets"] != 3 {
+		t.Errorf("markets requested %d times, want 3 after NoCache and ClearCache", requests["/markets"])
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.AssetsContext(ctx); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if requests["/assets"] != 2 {
+		t.Errorf("assets requested %d times, want 2 once the entry expired", requests["/assets"])
+	}
+}
+
+func TestWithCacheLiveEndpoints(t *testing.T) {
+	requests := map[string]int{}
+	url := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		fmt.Fprintf(w, `{"result":{"price":%d}}`, requests[r.URL.Path])
+	})
+
+	c := NewClient(WithBaseURL(url), WithCache(time.Hour))
+	for i := 1; i <= 2; i++ {
+		price, err := c.MarketPrice("kraken", "btcusd")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if price != float64(i) {
+			t.Errorf("price = %v, want %v", price, i)
+		}
+	}
+
+	c = NewClient(WithBaseURL(url), WithCache(time.Hour), WithEndpointCacheTTL("MarketPrice", time.Hour))
+	for i := 0; i < 2; i++ {
+		if _, err := c.MarketPrice("kraken", "gdax"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if requests["/markets/kraken/gdax/price"] != 1 {
+		t.Errorf("price requested %d times, want 1 once opted in", requests["/markets/kraken/gdax/price"])
+	}
+}
+
+func TestCacheSweepsExpiredEntries(t *testing.T) {
+	cache := NewClient(WithCache(time.Hour)).cache
+
+	for i := 0; i < 100; i++ {
+		cache.put(fmt.Sprintf("markets?cursor=%d", i), cacheEntry{}, time.Millisecond)
+	}
+	time.Sleep(5 * time.Millisecond)
+	for i := 0; i < 100; i++ {
+		cache.put(fmt.Sprintf("assets?cursor=%d", i), cacheEntry{}, time.Hour)
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	for url := range cache.entries {
+		if strings.HasPrefix(url, "markets") {
+			t.Fatalf("expired entry %q was kept", url)
+		}
+	}
+	if len(cache.entries) != 100 {
+		t.Errorf("cache holds %d entries, want 100", len(cache.entries))
+	}
+}
+
+func TestEndpointName(t *testing.T) {
+	cases := map[string]string{
+		"assets":                                "Assets",
+		"assets/btc":                            "Asset",
+		"markets":                               "Markets",
+		"markets/prices":                        "AggregratePrices",
+		"markets/kraken":                        "ExchangeMarkets",
+		"markets/kraken/btcusd":                 "Market",
+		"markets/kraken/btcusd/price":           "MarketPrice",
+		"markets/kraken/btcusd/ohlc?periods=60": "MarketOHLC",
+		"nowhere/at/all":                        "",
+	}
+	for path, expected := range cases {
+		if name := endpointName(path); name != expected {
+			t.Errorf("endpointName(%q) = %q, want %q", path, name, expected)
+		}
+	}
+}
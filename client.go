@@ -0,0 +1,167 @@
+package cryptowatch
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Client requests information from the cryptowatch api. Its methods are safe
+// for concurrent use.
+type Client struct {
+	httpClient  *http.Client
+	baseURL     string
+	apiKey      string
+	listings    listingCache
+	limiter     *limiter
+	concurrency int
+	retry       *retryPolicy
+	cache       *responseCache
+	observer    Observer
+
+	mu        sync.Mutex
+	allowance Allowance
+}
+
+// ClientConfig is a read-only snapshot of a Client's configuration. It never
+// contains the api key itself, only whether one is set.
+type ClientConfig struct {
+	BaseURL   string
+	Timeout   time.Duration
+	APIKeySet bool
+	RateLimit float64
+	RateBurst int
+
+	MaxAttempts    int
+	RetryBaseDelay time.Duration
+
+	Concurrency int
+	CacheTTL    time.Duration
+}
+
+// Observer is called after every request a Client sends with the endpoint it
+// went to (see WithEndpointCacheTTL for the names), the response's status code,
+// or 0 when no response arrived, how long it took and the allowance it cost.
+type Observer func(endpoint string, status int, duration time.Duration, cost float64)
+
+// Option configures a Client
+type Option func(*Client)
+
+// DefaultClient is the Client used by the package-level functions.
+var DefaultClient = NewClient()
+
+// NewClient returns a Client configured by options.
+func NewClient(options ...Option) *Client {
+	c := &Client{httpClient: http.DefaultClient, baseURL: base, concurrency: batchConcurrency}
+
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// WithHTTPClient makes the Client send its requests through httpClient, e.g. to
+// set a timeout, proxy or custom transport.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithBaseURL points the Client at baseURL instead of the public api, e.g. an
+// httptest.Server or a mirror. An empty baseURL is ignored.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		if baseURL != "" {
+			c.baseURL = strings.TrimSuffix(baseURL, "/") + "/"
+		}
+	}
+}
+
+// WithAPIKey authenticates the Client's requests with apiKey for a higher
+// allowance. The key is sent in the X-CW-API-Key header and never included in
+// errors.
+func WithAPIKey(apiKey string) Option {
+	return func(c *Client) {
+		c.apiKey = apiKey
+	}
+}
+
+// WithRateLimit throttles the Client to perSecond requests per second with
+// bursts of up to burst requests. Requests wait for their turn and give up if
+// their context is done first. A perSecond or burst that is not positive
+// leaves the Client unthrottled.
+func WithRateLimit(perSecond float64, burst int) Option {
+	return func(c *Client) {
+		if perSecond > 0 && burst > 0 {
+			c.limiter = newLimiter(perSecond, burst)
+		} else {
+			c.limiter = nil
+		}
+	}
+}
+
+// WithConcurrency sets how many requests the batch helpers, such as Summaries,
+// have in flight at once. It defaults to 8 and values below 1 are ignored.
+func WithConcurrency(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.concurrency = n
+		}
+	}
+}
+
+// WithObserver calls observer after every request the Client sends, failed ones
+// included, e.g. to record metrics. Retried requests are observed once per
+// attempt, while results served from the cache are not observed at all.
+func WithObserver(observer Observer) Option {
+	return func(c *Client) {
+		c.observer = observer
+	}
+}
+
+// Config returns a snapshot of the Client's configuration that is safe to log.
+func (c *Client) Config() ClientConfig {
+	config := ClientConfig{
+		BaseURL:     c.baseURL,
+		Timeout:     c.httpClient.Timeout,
+		APIKeySet:   c.apiKey != "",
+		Concurrency: c.concurrency,
+	}
+
+	if c.limiter != nil {
+		config.RateLimit = c.limiter.rate
+		config.RateBurst = c.limiter.burst
+	}
+	if c.retry != nil {
+		config.MaxAttempts = c.retry.maxAttempts
+		config.RetryBaseDelay = c.retry.baseDelay
+	}
+	if c.cache != nil {
+		config.CacheTTL = c.cache.ttl
+	}
+	return config
+}
+
+// endpoint builds the url of the named index against the Client's base url,
+// escaping each argument as a path segment.
+func (c *Client) endpoint(name string, args ...interface{}) string {
+	segments := make([]interface{}, len(args))
+	for i, arg := range args {
+		segments[i] = url.PathEscape(fmt.Sprint(arg))
+	}
+	return c.baseURL + fmt.Sprintf(indexes[name], segments...)
+}
+
+// LastAllowance returns the allowance reported by the most recent response that
+// carried one, or the zero Allowance before any has.
+func (c *Client) LastAllowance() Allowance {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.allowance
+}
@@ -0,0 +1,240 @@
+package cryptowatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// roundTripper adapts a function to http.RoundTripper.
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (fn roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	var requested []string
+	httpClient := &http.Client{Transport: roundTripper(func(req *http.Request) (*http.Response, error) {
+		requested = append(requested, req.URL.String())
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"result":{"price":42}}`)),
+			Request:    req,
+		}, nil
+	})}
+
+	c := NewClient(WithHTTPClient(httpClient))
+	price, err := c.MarketPrice("kraken", "btcusd")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price != 42 {
+		t.Errorf("price = %v, want 42", price)
+	}
+	if len(requested) != 1 || requested[0] != base+"markets/kraken/btcusd/price" {
+		t.Errorf("requested %v, want the kraken btcusd price route", requested)
+	}
+}
+
+func TestWithAPIKey(t *testing.T) {
+	const key = "ABCDEFGHIJ0123456789"
+	var headers []string
+	url := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Get("X-CW-API-Key"))
+		if r.URL.Path == "/markets" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"Invalid API key"}`)
+			return
+		}
+		fmt.Fprint(w, `{"result":{"price":42}}`)
+	})
+
+	if _, err := NewClient(WithBaseURL(url), WithAPIKey(key)).MarketPrice("kraken", "btcusd"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewClient(WithBaseURL(url)).MarketPrice("kraken", "btcusd"); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{key, ""}; !reflect.DeepEqual(headers, expected) {
+		t.Errorf("X-CW-API-Key headers = %q, want %q", headers, expected)
+	}
+
+	_, err := NewClient(WithBaseURL(url), WithAPIKey(key)).Markets()
+	if err == nil || strings.Contains(err.Error(), key) {
+		t.Errorf("err = %v, want an error that does not reveal the key", err)
+	}
+}
+
+func TestWithBaseURL(t *testing.T) {
+	url := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/markets" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":"Route not found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"result":[{"exchange":"kraken","pair":"btcusd","active":true}]}`)
+	})
+
+	for _, baseURL := range []string{url, url + "/"} {
+		markets, err := NewClient(WithBaseURL(baseURL)).Markets()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(markets) != 1 || markets[0].Exchange != "kraken" {
+			t.Errorf("markets from %s = %+v", baseURL, markets)
+		}
+	}
+
+	if config := NewClient(WithBaseURL(url)).Config(); config.BaseURL != url+"/" {
+		t.Errorf("BaseURL = %q, want %q", config.BaseURL, url+"/")
+	}
+}
+
+func TestConfig(t *testing.T) {
+	c := NewClient(WithHTTPClient(&http.Client{Timeout: 5 * time.Second}), WithAPIKey("secret"))
+
+	expected := ClientConfig{BaseURL: base, Timeout: 5 * time.Second, APIKeySet: true, Concurrency: batchConcurrency}
+	if config := c.Config(); config != expected {
+		t.Errorf("Config() = %+v, want %+v", config, expected)
+	}
+
+	expected = ClientConfig{BaseURL: base, RateLimit: 2, RateBurst: 5, MaxAttempts: 3, RetryBaseDelay: time.Second, Concurrency: 2, CacheTTL: time.Minute}
+	if config := NewClient(WithRateLimit(2, 5), WithRetry(3, time.Second), WithConcurrency(2), WithCache(time.Minute)).Config(); config != expected {
+		t.Errorf("throttled Config() = %+v, want %+v", config, expected)
+	}
+
+	expected = ClientConfig{BaseURL: base, Concurrency: batchConcurrency}
+	if config := NewClient().Config(); config != expected {
+		t.Errorf("default Config() = %+v, want %+v", config, expected)
+	}
+}
+
+func TestLastAllowance(t *testing.T) {
+	url := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"price":42},"allowance":{"cost":0.005,"remaining":9.995,"remainingPaid":0,"upgrade":"For unlimited API access, create an account"}}`)
+	})
+
+	c := NewClient(WithBaseURL(url))
+	if allowance := c.LastAllowance(); allowance != (Allowance{}) {
+		t.Errorf("allowance before any request = %+v, want zero", allowance)
+	}
+
+	if _, err := c.MarketPrice("kraken", "btcusd"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Allowance{Cost: 0.005, Remaining: 9.995, Upgrade: "For unlimited API access, create an account"}
+	if allowance := c.LastAllowance(); allowance != expected {
+		t.Errorf("LastAllowance() = %+v, want %+v", allowance, expected)
+	}
+}
+
+func TestWithRateLimit(t *testing.T) {
+	var requests int
+	url := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"result":{"price":42}}`)
+	})
+
+	c := NewClient(WithBaseURL(url), WithRateLimit(20, 2))
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := c.MarketPrice("kraken", "btcusd"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("3 requests with a burst of 2 at 20/s took %v, want at least 50ms", elapsed)
+	}
+
+	c = NewClient(WithBaseURL(url), WithRateLimit(0.001, 1))
+	if _, err := c.MarketPrice("kraken", "btcusd"); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.MarketPriceContext(ctx, "kraken", "btcusd"); err != context.DeadlineExceeded {
+		t.Errorf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if requests != 4 {
+		t.Errorf("server saw %d requests, want 4", requests)
+	}
+}
+
+func TestWithObserver(t *testing.T) {
+	type observation struct {
+		endpoint string
+		status   int
+		cost     float64
+	}
+	var observed []observation
+	observer := func(endpoint string, status int, duration time.Duration, cost float64) {
+		if duration <= 0 {
+			t.Errorf("%s observed taking %v", endpoint, duration)
+		}
+		observed = append(observed, observation{endpoint, status, cost})
+	}
+
+	url := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/markets" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":"Route not found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"result":{"price":42},"allowance":{"cost":0.005,"remaining":9.995}}`)
+	})
+
+	c := NewClient(WithBaseURL(url), WithObserver(observer))
+	if _, err := c.MarketPrice("kraken", "btcusd"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Markets(); err == nil {
+		t.Fatal("expected an error for a 404")
+	}
+
+	failing := &http.Client{Transport: roundTripper(func(req *http.Request) (*http.Response, error) {
+		time.Sleep(time.Millisecond)
+		return nil, errors.New("connection refused")
+	})}
+	c = NewClient(WithHTTPClient(failing), WithObserver(observer))
+	if _, err := c.AggregratePrices(); err == nil {
+		t.Fatal("expected a transport error")
+	}
+
+	expected := []observation{
+		{"MarketPrice", 200, 0.005},
+		{"Markets", 404, 0},
+		{"AggregratePrices", 0, 0},
+	}
+	if !reflect.DeepEqual(observed, expected) {
+		t.Errorf("observed %+v, want %+v", observed, expected)
+	}
+}
+
+func TestEndpointEscaping(t *testing.T) {
+	var paths []string
+	serve(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		fmt.Fprint(w, `{"result":{"price":42}}`)
+	})
+
+	if _, err := MarketPrice("kraken", "btc/usd?x=1#frag"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AssetMarkets("b c"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"/markets/kraken/btc%2Fusd%3Fx=1%23frag/price", "/assets/b%20c"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("requested %q, want %q", paths, expected)
+	}
+}
@@ -4,30 +4,73 @@
 package cryptowatch
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 )
 
-// Assets returns all assets (in no particular order).
-func Assets() ([]Asset, error) {
+// Assets returns the first page of assets (in no particular order). Use
+// AssetsPage or AllAssets to see the rest.
+func (c *Client) Assets() ([]Asset, error) {
+	return c.AssetsContext(context.Background())
+}
+
+// AssetsContext is like Assets but aborts the request when ctx is done.
+func (c *Client) AssetsContext(ctx context.Context) ([]Asset, error) {
 	var assets []Asset
-	res, err := request(indexes["Assets"])
+	res, err := c.request(ctx, c.endpoint("Assets"))
 
 	if res != nil {
-		json.Unmarshal(res, &assets)
+		err = json.Unmarshal(res, &assets)
 	}
 	return assets, err
 }
 
+// AssetsPage returns one page of all assets, starting after cursor, or from the
+// first page when cursor is empty. Up to limit results are returned, or the
+// api's default when limit is zero. next is the cursor of the following page
+// and is empty once there are no more.
+func (c *Client) AssetsPage(cursor string, limit int) (assets []Asset, next string, err error) {
+	return c.AssetsPageContext(context.Background(), cursor, limit)
+}
+
+// AssetsPageContext is like AssetsPage but aborts the request when ctx is done.
+func (c *Client) AssetsPageContext(ctx context.Context, cursor string, limit int) (assets []Asset, next string, err error) {
+	query, err := pageQuery(cursor, limit)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	res, next, err := c.requestPage(ctx, withQuery(c.endpoint("Assets"), query))
+
+	if res != nil {
+		err = json.Unmarshal(res, &assets)
+	}
+	return assets, next, err
+}
+
 // AssetMarkets returns all markets which have this asset as a base or quote.
-func AssetMarkets(asset string) (DetailedAsset, error) {
+func (c *Client) AssetMarkets(asset string) (DetailedAsset, error) {
+	return c.AssetMarketsContext(context.Background(), asset)
+}
+
+// AssetMarketsContext is like AssetMarkets but aborts the request when ctx is done.
+func (c *Client) AssetMarketsContext(ctx context.Context, asset string) (DetailedAsset, error) {
 	var markets DetailedAsset
-	url := fmt.Sprintf(indexes["Asset"], asset)
-	res, err := request(url)
+	url := c.endpoint("Asset", asset)
+	res, err := c.request(ctx, url)
 
 	if res != nil {
 		err = json.Unmarshal(res, &markets)
@@ -35,10 +78,16 @@ func AssetMarkets(asset string) (DetailedAsset, error) {
 	return markets, err
 }
 
-// Pairs returns all pairs (in no particular order).
-func Pairs() ([]Pair, error) {
+// Pairs returns the first page of pairs (in no particular order). Use PairsPage
+// or AllPairs to see the rest.
+func (c *Client) Pairs() ([]Pair, error) {
+	return c.PairsContext(context.Background())
+}
+
+// PairsContext is like Pairs but aborts the request when ctx is done.
+func (c *Client) PairsContext(ctx context.Context) ([]Pair, error) {
 	var pairs []Pair
-	res, err := request(indexes["Pairs"])
+	res, err := c.request(ctx, c.endpoint("Pairs"))
 
 	if res != nil {
 		err = json.Unmarshal(res, &pairs)
@@ -47,11 +96,40 @@ func Pairs() ([]Pair, error) {
 	return pairs, err
 }
 
+// PairsPage returns one page of all pairs, starting after cursor, or from the
+// first page when cursor is empty. Up to limit results are returned, or the
+// api's default when limit is zero. next is the cursor of the following page
+// and is empty once there are no more.
+func (c *Client) PairsPage(cursor string, limit int) (pairs []Pair, next string, err error) {
+	return c.PairsPageContext(context.Background(), cursor, limit)
+}
+
+// PairsPageContext is like PairsPage but aborts the request when ctx is done.
+func (c *Client) PairsPageContext(ctx context.Context, cursor string, limit int) (pairs []Pair, next string, err error) {
+	query, err := pageQuery(cursor, limit)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	res, next, err := c.requestPage(ctx, withQuery(c.endpoint("Pairs"), query))
+
+	if res != nil {
+		err = json.Unmarshal(res, &pairs)
+	}
+	return pairs, next, err
+}
+
 // PairMarkets lists all markets for this pair.
-func PairMarkets(pair string) (PairMarket, error) {
+func (c *Client) PairMarkets(pair string) (PairMarket, error) {
+	return c.PairMarketsContext(context.Background(), pair)
+}
+
+// PairMarketsContext is like PairMarkets but aborts the request when ctx is done.
+func (c *Client) PairMarketsContext(ctx context.Context, pair string) (PairMarket, error) {
 	var markets PairMarket
-	url := fmt.Sprintf(indexes["Pair"], pair)
-	res, err := request(url)
+	url := c.endpoint("Pair", pair)
+	res, err := c.request(ctx, url)
 
 	if res != nil {
 		err = json.Unmarshal(res, &markets)
@@ -60,10 +138,16 @@ func PairMarkets(pair string) (PairMarket, error) {
 	return markets, err
 }
 
-// Exchanges returns a list of all supported exchanges.
-func Exchanges() ([]GeneralExchange, error) {
+// Exchanges returns the first page of supported exchanges. Use ExchangesPage or
+// AllExchanges to see the rest.
+func (c *Client) Exchanges() ([]GeneralExchange, error) {
+	return c.ExchangesContext(context.Background())
+}
+
+// ExchangesContext is like Exchanges but aborts the request when ctx is done.
+func (c *Client) ExchangesContext(ctx context.Context) ([]GeneralExchange, error) {
 	var exchanges []GeneralExchange
-	res, err := request(indexes["Exchanges"])
+	res, err := c.request(ctx, c.endpoint("Exchanges"))
 
 	if res != nil {
 		err = json.Unmarshal(res, &exchanges)
@@ -72,11 +156,40 @@ func Exchanges() ([]GeneralExchange, error) {
 	return exchanges, err
 }
 
+// ExchangesPage returns one page of supported exchanges, starting after cursor, or from the
+// first page when cursor is empty. Up to limit results are returned, or the
+// api's default when limit is zero. next is the cursor of the following page
+// and is empty once there are no more.
+func (c *Client) ExchangesPage(cursor string, limit int) (exchanges []GeneralExchange, next string, err error) {
+	return c.ExchangesPageContext(context.Background(), cursor, limit)
+}
+
+// ExchangesPageContext is like ExchangesPage but aborts the request when ctx is done.
+func (c *Client) ExchangesPageContext(ctx context.Context, cursor string, limit int) (exchanges []GeneralExchange, next string, err error) {
+	query, err := pageQuery(cursor, limit)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	res, next, err := c.requestPage(ctx, withQuery(c.endpoint("Exchanges"), query))
+
+	if res != nil {
+		err = json.Unmarshal(res, &exchanges)
+	}
+	return exchanges, next, err
+}
+
 // Exchange returns a single exchange, with associated routes.
-func Exchange(name string) (DetailedExchange, error) {
+func (c *Client) Exchange(name string) (DetailedExchange, error) {
+	return c.ExchangeContext(context.Background(), name)
+}
+
+// ExchangeContext is like Exchange but aborts the request when ctx is done.
+func (c *Client) ExchangeContext(ctx context.Context, name string) (DetailedExchange, error) {
 	var exchange DetailedExchange
-	url := fmt.Sprintf(indexes["Exchange"], name)
-	res, err := request(url)
+	url := c.endpoint("Exchange", name)
+	res, err := c.request(ctx, url)
 
 	if res != nil {
 		err = json.Unmarshal(res, &exchange)
@@ -85,10 +198,39 @@ func Exchange(name string) (DetailedExchange, error) {
 	return exchange, err
 }
 
-// Markets returns a list of all supported markets.
-func Markets() ([]GeneralMarket, error) {
+// ExchangeMarkets returns the markets on an exchange. An exchange without
+// markets returns an empty slice and no error.
+func (c *Client) ExchangeMarkets(exchange string) ([]GeneralMarket, error) {
+	return c.ExchangeMarketsContext(context.Background(), exchange)
+}
+
+// ExchangeMarketsContext is like ExchangeMarkets but aborts the request when ctx
+// is done.
+func (c *Client) ExchangeMarketsContext(ctx context.Context, exchange string) ([]GeneralMarket, error) {
+	markets := []GeneralMarket{}
+	url := c.endpoint("ExchangeMarkets", exchange)
+	res, err := c.request(ctx, url)
+
+	if errors.Is(err, ErrEmptyResult) {
+		return markets, nil
+	}
+	if res != nil {
+		err = json.Unmarshal(res, &markets)
+	}
+
+	return markets, err
+}
+
+// Markets returns the first page of supported markets. Use MarketsPage or
+// AllMarkets to see the rest.
+func (c *Client) Markets() ([]GeneralMarket, error) {
+	return c.MarketsContext(context.Background())
+}
+
+// MarketsContext is like Markets but aborts the request when ctx is done.
+func (c *Client) MarketsContext(ctx context.Context) ([]GeneralMarket, error) {
 	var markets []GeneralMarket
-	res, err := request(indexes["Markets"])
+	res, err := c.request(ctx, c.endpoint("Markets"))
 
 	if res != nil {
 		err = json.Unmarshal(res, &markets)
@@ -97,11 +239,40 @@ func Markets() ([]GeneralMarket, error) {
 	return markets, err
 }
 
+// MarketsPage returns one page of supported markets, starting after cursor, or from the
+// first page when cursor is empty. Up to limit results are returned, or the
+// api's default when limit is zero. next is the cursor of the following page
+// and is empty once there are no more.
+func (c *Client) MarketsPage(cursor string, limit int) (markets []GeneralMarket, next string, err error) {
+	return c.MarketsPageContext(context.Background(), cursor, limit)
+}
+
+// MarketsPageContext is like MarketsPage but aborts the request when ctx is done.
+func (c *Client) MarketsPageContext(ctx context.Context, cursor string, limit int) (markets []GeneralMarket, next string, err error) {
+	query, err := pageQuery(cursor, limit)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	res, next, err := c.requestPage(ctx, withQuery(c.endpoint("Markets"), query))
+
+	if res != nil {
+		err = json.Unmarshal(res, &markets)
+	}
+	return markets, next, err
+}
+
 // Market returns a single market, with associated routes.
-func Market(exchange, pair string) (DetailedMarket, error) {
+func (c *Client) Market(exchange, pair string) (DetailedMarket, error) {
+	return c.MarketContext(context.Background(), exchange, pair)
+}
+
+// MarketContext is like Market but aborts the request when ctx is done.
+func (c *Client) MarketContext(ctx context.Context, exchange, pair string) (DetailedMarket, error) {
 	var market DetailedMarket
-	url := fmt.Sprintf(indexes["Market"], exchange, pair)
-	res, err := request(url)
+	url := c.endpoint("Market", exchange, pair)
+	res, err := c.request(ctx, url)
 
 	if res != nil {
 		err = json.Unmarshal(res, &market)
@@ -111,10 +282,15 @@ func Market(exchange, pair string) (DetailedMarket, error) {
 }
 
 // MarketPrice returns a market’s last price.
-func MarketPrice(exchange, pair string) (float64, error) {
+func (c *Client) MarketPrice(exchange, pair string) (float64, error) {
+	return c.MarketPriceContext(context.Background(), exchange, pair)
+}
+
+// MarketPriceContext is like MarketPrice but aborts the request when ctx is done.
+func (c *Client) MarketPriceContext(ctx context.Context, exchange, pair string) (float64, error) {
 	var price float64
-	url := fmt.Sprintf(indexes["MarketPrice"], exchange, pair)
-	res, err := request(url)
+	url := c.endpoint("MarketPrice", exchange, pair)
+	res, err := c.request(ctx, url)
 
 	if res != nil {
 		var resp map[string]float64
@@ -129,10 +305,15 @@ func MarketPrice(exchange, pair string) (float64, error) {
 }
 
 // MarketSummary returns a market’s last price as well as other stats based on a 24-hour sliding window.
-func MarketSummary(exchange, pair string) (Summary, error) {
+func (c *Client) MarketSummary(exchange, pair string) (Summary, error) {
+	return c.MarketSummaryContext(context.Background(), exchange, pair)
+}
+
+// MarketSummaryContext is like MarketSummary but aborts the request when ctx is done.
+func (c *Client) MarketSummaryContext(ctx context.Context, exchange, pair string) (Summary, error) {
 	var summary Summary
-	url := fmt.Sprintf(indexes["MarketSummary"], exchange, pair)
-	res, err := request(url)
+	url := c.endpoint("MarketSummary", exchange, pair)
+	res, err := c.request(ctx, url)
 
 	if res != nil {
 		err = json.Unmarshal(res, &summary)
@@ -142,10 +323,31 @@ func MarketSummary(exchange, pair string) (Summary, error) {
 }
 
 // Trades returns a market’s most recent trades, incrementing chronologically.
-func Trades(exchange, pair string) ([]Trade, error) {
+func (c *Client) Trades(exchange, pair string) ([]Trade, error) {
+	return c.TradesContext(context.Background(), exchange, pair)
+}
+
+// TradesContext is like Trades but aborts the request when ctx is done.
+func (c *Client) TradesContext(ctx context.Context, exchange, pair string) ([]Trade, error) {
+	return c.TradesWithOptionsContext(ctx, exchange, pair, TradeOptions{})
+}
+
+// TradesWithOptions returns up to options.Limit of a market’s trades since options.Since, incrementing chronologically.
+func (c *Client) TradesWithOptions(exchange, pair string, options TradeOptions) ([]Trade, error) {
+	return c.TradesWithOptionsContext(context.Background(), exchange, pair, options)
+}
+
+// TradesWithOptionsContext is like TradesWithOptions but aborts the request when ctx is done.
+func (c *Client) TradesWithOptionsContext(ctx context.Context, exchange, pair string, options TradeOptions) ([]Trade, error) {
 	var trades []Trade
-	url := fmt.Sprintf(indexes["MarketTrades"], exchange, pair)
-	res, err := request(url)
+	query, err := options.values()
+
+	if err != nil {
+		return nil, err
+	}
+
+	url := withQuery(c.endpoint("MarketTrades", exchange, pair), query)
+	res, err := c.request(ctx, url)
 
 	if res != nil {
 		err = json.Unmarshal(res, &trades)
@@ -155,10 +357,31 @@ func Trades(exchange, pair string) ([]Trade, error) {
 }
 
 // OrderBook returns a market’s order book.
-func OrderBook(exchange, pair string) (MarketOrderBook, error) {
+func (c *Client) OrderBook(exchange, pair string) (MarketOrderBook, error) {
+	return c.OrderBookContext(context.Background(), exchange, pair)
+}
+
+// OrderBookContext is like OrderBook but aborts the request when ctx is done.
+func (c *Client) OrderBookContext(ctx context.Context, exchange, pair string) (MarketOrderBook, error) {
+	return c.OrderBookWithOptionsContext(ctx, exchange, pair, OrderBookOptions{})
+}
+
+// OrderBookWithOptions returns a market’s order book truncated and aggregated as described by options.
+func (c *Client) OrderBookWithOptions(exchange, pair string, options OrderBookOptions) (MarketOrderBook, error) {
+	return c.OrderBookWithOptionsContext(context.Background(), exchange, pair, options)
+}
+
+// OrderBookWithOptionsContext is like OrderBookWithOptions but aborts the request when ctx is done.
+func (c *Client) OrderBookWithOptionsContext(ctx context.Context, exchange, pair string, options OrderBookOptions) (MarketOrderBook, error) {
 	var orderbook MarketOrderBook
-	url := fmt.Sprintf(indexes["MarketOrderBook"], exchange, pair)
-	res, err := request(url)
+	query, err := options.values()
+
+	if err != nil {
+		return orderbook, err
+	}
+
+	url := withQuery(c.endpoint("MarketOrderBook", exchange, pair), query)
+	res, err := c.request(ctx, url)
 
 	if res != nil {
 		err = json.Unmarshal(res, &orderbook)
@@ -168,10 +391,25 @@ func OrderBook(exchange, pair string) (MarketOrderBook, error) {
 }
 
 // Ohlc returns a market’s OHLC candlestick data. Returns data as lists of lists of numbers for each time period integer.
-func Ohlc(exchange, pair string) (OHLC, error) {
+func (c *Client) Ohlc(exchange, pair string) (OHLC, error) {
+	return c.OhlcContext(context.Background(), exchange, pair)
+}
+
+// OhlcContext is like Ohlc but aborts the request when ctx is done.
+func (c *Client) OhlcContext(ctx context.Context, exchange, pair string) (OHLC, error) {
+	return c.OhlcWithOptionsContext(ctx, exchange, pair, OhlcOptions{})
+}
+
+// OhlcWithOptions returns a market’s OHLC candlestick data restricted to the periods and time range in options.
+func (c *Client) OhlcWithOptions(exchange, pair string, options OhlcOptions) (OHLC, error) {
+	return c.OhlcWithOptionsContext(context.Background(), exchange, pair, options)
+}
+
+// OhlcWithOptionsContext is like OhlcWithOptions but aborts the request when ctx is done.
+func (c *Client) OhlcWithOptionsContext(ctx context.Context, exchange, pair string, options OhlcOptions) (OHLC, error) {
 	var ohlc OHLC
-	url := fmt.Sprintf(indexes["MarketOHLC"], exchange, pair)
-	res, err := request(url)
+	url := withQuery(c.endpoint("MarketOHLC", exchange, pair), options.values())
+	res, err := c.request(ctx, url)
 
 	if res != nil {
 		err = json.Unmarshal(res, &ohlc)
@@ -181,9 +419,14 @@ func Ohlc(exchange, pair string) (OHLC, error) {
 }
 
 // AggregratePrices returns the current price for all supported markets. Some values may be out of date by a few seconds.
-func AggregratePrices() (AggregratePrice, error) {
+func (c *Client) AggregratePrices() (AggregratePrice, error) {
+	return c.AggregratePricesContext(context.Background())
+}
+
+// AggregratePricesContext is like AggregratePrices but aborts the request when ctx is done.
+func (c *Client) AggregratePricesContext(ctx context.Context) (AggregratePrice, error) {
 	var prices AggregratePrice
-	res, err := request(indexes["AggregratePrices"])
+	res, err := c.request(ctx, c.endpoint("AggregratePrices"))
 
 	if res != nil {
 		err = json.Unmarshal(res, &prices)
@@ -193,9 +436,14 @@ func AggregratePrices() (AggregratePrice, error) {
 }
 
 // AggregrateSummaries returns the market summary for all supported markets. Some values may be out of date by a few seconds.
-func AggregrateSummaries() (AggregrateSummary, error) {
+func (c *Client) AggregrateSummaries() (AggregrateSummary, error) {
+	return c.AggregrateSummariesContext(context.Background())
+}
+
+// AggregrateSummariesContext is like AggregrateSummaries but aborts the request when ctx is done.
+func (c *Client) AggregrateSummariesContext(ctx context.Context) (AggregrateSummary, error) {
 	var summaries AggregrateSummary
-	res, err := request(indexes["AggregrateSummaries"])
+	res, err := c.request(ctx, c.endpoint("AggregrateSummaries"))
 
 	if res != nil {
 		err = json.Unmarshal(res, &summaries)
@@ -204,40 +452,269 @@ func AggregrateSummaries() (AggregrateSummary, error) {
 	return summaries, err
 }
 
-func request(url string) ([]byte, error) {
-	var data interface{}
-	resp, err := http.Get(url)
+// Raw requests path, relative to the Client's base url and optionally with a
+// query, and returns the result unwrapped from the api's envelope for the caller
+// to decode. It goes through the same error handling, retries, rate limiting
+// and cache as every other request, so it suits endpoints and fields this
+// package does not model yet.
+func (c *Client) Raw(ctx context.Context, path string) (json.RawMessage, error) {
+	return c.request(ctx, c.baseURL+strings.TrimPrefix(path, "/"))
+}
+
+// withQuery appends the encoded query to endpoint, if there is any.
+func withQuery(endpoint string, query url.Values) string {
+	if len(query) == 0 {
+		return endpoint
+	}
+	return endpoint + "?" + query.Encode()
+}
+
+// request requests url and returns the raw result for the caller to decode into
+// its own type, so the result is only ever decoded once.
+func (c *Client) request(ctx context.Context, url string) (json.RawMessage, error) {
+	result, _, err := c.requestPage(ctx, url)
+	return result, err
+}
+
+// envelope is the wrapper the api puts around every response.
+type envelope struct {
+	Result    json.RawMessage `json:"result"`
+	Error     string          `json:"error"`
+	Allowance json.RawMessage `json:"allowance"`
+	Cursor    *struct {
+		Last    string `json:"last"`
+		HasMore bool   `json:"hasMore"`
+	} `json:"cursor"`
+}
+
+// requestPage requests url and also returns the cursor of the next page, which
+// is empty when there are no more results or the endpoint is not paginated.
+// Results are answered from and stored in the Client's cache, if it has one,
+// unless ctx bypasses it.
+func (c *Client) requestPage(ctx context.Context, url string) (result json.RawMessage, next string, err error) {
+	var ttl time.Duration
+	cached := c.cache != nil && ctx.Value(noCacheKey{}) == nil
+
+	if cached {
+		if entry, ok := c.cache.get(url); ok {
+			return entry.result, entry.next, nil
+		}
+		ttl = c.cache.ttlFor(strings.TrimPrefix(url, c.baseURL))
+	}
+
+	response, err := c.send(ctx, url)
 
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
+	if response.Cursor != nil && response.Cursor.HasMore {
+		next = response.Cursor.Last
+	}
+	if cached && ttl > 0 {
+		c.cache.put(url, cacheEntry{result: response.Result, next: next}, ttl)
+	}
+	return response.Result, next, nil
+}
+
+// send requests url, retrying as configured, and returns the envelope of a
+// successful response.
+func (c *Client) send(ctx context.Context, url string) (*envelope, error) {
+	if c.retry == nil {
+		response, _, _, err := c.attempt(ctx, url)
+		return response, err
+	}
+
+	for attempts := 1; ; attempts++ {
+		response, retryAfter, retryable, err := c.attempt(ctx, url)
+
+		if err == nil {
+			return response, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// a wait longer than any backoff is left to the caller to schedule
+		if !retryable || attempts >= c.retry.maxAttempts || retryAfter > maxRetryDelay {
+			return nil, &RetryError{Attempts: attempts, Err: err}
+		}
+
+		delay := retryAfter
+		if delay <= 0 {
+			delay = c.retry.backoff(attempts)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// attempt sends a single request and returns its envelope, which is known to
+// carry a result, when it succeeds. The body is decoded as it streams in, so
+// only the start of it is kept for errors about bodies that are not json. On
+// failure it reports whether the request is worth retrying, i.e. the connection
+// failed, the api throttled it or the server errored, and how long the api
+// asked to wait first, if it did.
+func (c *Client) attempt(ctx context.Context, url string) (response *envelope, retryAfter time.Duration, retryable bool, err error) {
+	if c.limiter != nil {
+		if err := c.limiter.wait(ctx); err != nil {
+			return nil, 0, false, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 
 	if err != nil {
-		return nil, err
+		return nil, 0, false, err
+	}
+
+	// asking explicitly turns off the transport's own decompression, so bodies
+	// are decoded below whatever transport the Client uses
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+
+	if c.apiKey != "" {
+		req.Header.Set("X-CW-API-Key", c.apiKey)
+	}
+
+	var (
+		status int
+		cost   float64
+		start  = time.Now()
+	)
+	if c.observer != nil {
+		defer func() {
+			c.observer(endpointName(strings.TrimPrefix(url, c.baseURL)), status, time.Since(start), cost)
+		}()
 	}
 
-	err = json.Unmarshal(body, &data)
+	resp, err := c.httpClient.Do(req)
 
 	if err != nil {
-		return nil, err
+		return nil, 0, true, err
 	}
+	status = resp.StatusCode
 
-	// convert the response to a usable format
-	results := data.(map[string]interface{})
+	defer resp.Body.Close()
+	decoded, err := decompress(resp)
+
+	if err != nil {
+		return nil, 0, resp.StatusCode >= 500, err
+	}
+
+	defer decoded.Close()
+	body := bufio.NewReader(decoded)
+	head, err := body.Peek(maxSnippet + 1)
+
+	if err != nil && err != io.EOF {
+		return nil, 0, true, err
+	}
+
+	serverError := resp.StatusCode >= 500
+	head = append([]byte(nil), head...)
+	response = &envelope{}
+	decodeErr := json.NewDecoder(body).Decode(response)
+
+	if decodeErr != nil {
+		response = &envelope{}
+	} else if response.Allowance != nil {
+		if allowance, ok := c.recordAllowance(response.Allowance); ok {
+			cost = allowance.Cost
+		}
+	}
 
 	switch {
 	case resp.StatusCode == 429:
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			message := "Too Many Requests. Retry after " + retryAfter.String() + "."
+			return nil, retryAfter, true, &RateLimitError{RetryAfter: retryAfter, Message: message}
+		}
+
 		ttr := 60 - time.Now().Minute()
-		message := "Too Many Requests. Allowance resets in " + string(ttr) + " minutes."
-		return nil, errors.New(message)
+		message := "Too Many Requests. Allowance resets in " + strconv.Itoa(ttr) + " minutes."
+		return nil, 0, true, &RateLimitError{RetryAfter: time.Duration(ttr) * time.Minute, Message: message}
+	case resp.StatusCode == 401 || resp.StatusCode == 403:
+		return nil, 0, false, &AuthError{StatusCode: resp.StatusCode, Message: response.Error}
 	case resp.StatusCode != 200:
-		message := (results["error"]).(string)
-		return nil, errors.New(message)
+		return nil, 0, serverError, &ServerError{
+			StatusCode: resp.StatusCode,
+			Endpoint:   strings.TrimPrefix(url, c.baseURL),
+			Message:    response.Error,
+			Body:       snippet(head),
+		}
+	case decodeErr != nil:
+		// a body cut short may come through whole on a retry, a malformed one won't
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		malformed := errors.As(decodeErr, &syntaxErr) || errors.As(decodeErr, &typeErr)
+		return nil, 0, !malformed, unexpectedResponse(resp.StatusCode, head)
 	default:
-		results, err := json.Marshal(results["result"])
-		return results, err
+		if response.Result == nil {
+			return nil, 0, false, ErrNoResult
+		}
+		if string(response.Result) == "null" {
+			return nil, 0, false, ErrEmptyResult
+		}
+
+		return response, 0, false, nil
+	}
+}
+
+// maxErrorBody caps how much of an unsuccessful response's body is read.
+const maxErrorBody = 1 << 20
+
+// decompress returns the response body decoded according to its
+// Content-Encoding, which may be gzip, deflate or none. An empty body is
+// returned as is. So is the body of an unsuccessful response that does not
+// decompress, as proxies send their error pages uncompressed whatever the
+// headers say.
+func decompress(resp *http.Response) (io.ReadCloser, error) {
+	encoding := strings.ToLower(resp.Header.Get("Content-Encoding"))
+
+	if encoding != "gzip" && encoding != "deflate" {
+		return ioutil.NopCloser(resp.Body), nil
 	}
+
+	if resp.StatusCode != http.StatusOK {
+		raw, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+
+		if err != nil {
+			return nil, err
+		}
+		if decoded, err := newDecompressor(encoding, bytes.NewReader(raw)); err == nil {
+			return decoded, nil
+		}
+		return ioutil.NopCloser(bytes.NewReader(raw)), nil
+	}
+
+	body := bufio.NewReader(resp.Body)
+	if _, err := body.Peek(1); err == io.EOF {
+		return ioutil.NopCloser(body), nil
+	}
+	return newDecompressor(encoding, body)
+}
+
+// newDecompressor returns a reader decoding body, compressed with encoding.
+func newDecompressor(encoding string, body io.Reader) (io.ReadCloser, error) {
+	if encoding == "gzip" {
+		return gzip.NewReader(body)
+	}
+	return zlib.NewReader(body)
+}
+
+// recordAllowance keeps the allowance from a response for LastAllowance and
+// returns it. ok is false when it could not be decoded.
+func (c *Client) recordAllowance(raw json.RawMessage) (allowance Allowance, ok bool) {
+	if json.Unmarshal(raw, &allowance) != nil {
+		return allowance, false
+	}
+
+	c.mu.Lock()
+	c.allowance = allowance
+	c.mu.Unlock()
+	return allowance, true
 }
@@ -1,11 +1,286 @@
 package cryptowatch
 
 import (
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
+// serve starts a test server backed by handler and installs a DefaultClient
+// pointed at it for the duration of the test. It returns the server's url for
+// tests that build their own clients.
+func serve(t testing.TB, handler http.HandlerFunc) string {
+	srv := httptest.NewServer(handler)
+	savedClient := DefaultClient
+	DefaultClient = NewClient(WithBaseURL(srv.URL))
+
+	t.Cleanup(func() {
+		DefaultClient = savedClient
+		srv.Close()
+	})
+	return srv.URL
+}
+
+// serveResults serves each path's result wrapped in the api envelope and
+// returns the server's url.
+func serveResults(t *testing.T, results map[string]string) string {
+	return serve(t, func(w http.ResponseWriter, r *http.Request) {
+		result, ok := results[r.URL.Path]
+
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":"Route not found"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"result":%s}`, result)
+	})
+}
+
+// serveBlocked serves requests that never complete until the client goes away,
+// signalling on the returned channel as each one arrives.
+func serveBlocked(t *testing.T) <-chan struct{} {
+	arrived := make(chan struct{}, 1)
+	serve(t, func(w http.ResponseWriter, r *http.Request) {
+		arrived <- struct{}{}
+		<-r.Context().Done()
+	})
+	return arrived
+}
+
+func TestContextCancel(t *testing.T) {
+	calls := map[string]func(ctx context.Context) error{
+		"Assets": func(ctx context.Context) error {
+			_, err := AssetsContext(ctx)
+			return err
+		},
+		"AssetMarkets": func(ctx context.Context) error {
+			_, err := AssetMarketsContext(ctx, "btc")
+			return err
+		},
+		"Pairs": func(ctx context.Context) error {
+			_, err := PairsContext(ctx)
+			return err
+		},
+		"PairMarkets": func(ctx context.Context) error {
+			_, err := PairMarketsContext(ctx, "btcusd")
+			return err
+		},
+		"Exchanges": func(ctx context.Context) error {
+			_, err := ExchangesContext(ctx)
+			return err
+		},
+		"Exchange": func(ctx context.Context) error {
+			_, err := ExchangeContext(ctx, "kraken")
+			return err
+		},
+		"ExchangeMarkets": func(ctx context.Context) error {
+			_, err := ExchangeMarketsContext(ctx, "kraken")
+			return err
+		},
+		"Markets": func(ctx context.Context) error {
+			_, err := MarketsContext(ctx)
+			return err
+		},
+		"Market": func(ctx context.Context) error {
+			_, err := MarketContext(ctx, "kraken", "btcusd")
+			return err
+		},
+		"MarketPrice": func(ctx context.Context) error {
+			_, err := MarketPriceContext(ctx, "kraken", "btcusd")
+			return err
+		},
+		"MarketSummary": func(ctx context.Context) error {
+			_, err := MarketSummaryContext(ctx, "kraken", "btcusd")
+			return err
+		},
+		"Trades": func(ctx context.Context) error {
+			_, err := TradesContext(ctx, "kraken", "btcusd")
+			return err
+		},
+		"OrderBook": func(ctx context.Context) error {
+			_, err := OrderBookContext(ctx, "kraken", "btcusd")
+			return err
+		},
+		"Ohlc": func(ctx context.Context) error {
+			_, err := OhlcContext(ctx, "kraken", "btcusd")
+			return err
+		},
+		"AggregratePrices": func(ctx context.Context) error {
+			_, err := AggregratePricesContext(ctx)
+			return err
+		},
+		"AggregrateSummaries": func(ctx context.Context) error {
+			_, err := AggregrateSummariesContext(ctx)
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			arrived := serveBlocked(t)
+			ctx, cancel := context.WithCancel(context.Background())
+
+			go func() {
+				<-arrived
+				cancel()
+			}()
+
+			if err := call(ctx); !errors.Is(err, context.Canceled) {
+				t.Errorf("err = %v, want context.Canceled", err)
+			}
+		})
+	}
+}
+
+func TestCompressedResponses(t *testing.T) {
+	const envelope = `{"result":{"price":42}}`
+	var accepted []string
+	serve(t, func(w http.ResponseWriter, r *http.Request) {
+		accepted = append(accepted, r.Header.Get("Accept-Encoding"))
+
+		var compressed io.WriteCloser
+		// the exchange segment names the encoding to answer with
+		switch strings.Split(r.URL.Path, "/")[2] {
+		case "gzip":
+			w.Header().Set("Content-Encoding", "gzip")
+			compressed = gzip.NewWriter(w)
+		case "deflate":
+			w.Header().Set("Content-Encoding", "deflate")
+			compressed = zlib.NewWriter(w)
+		default:
+			fmt.Fprint(w, envelope)
+			return
+		}
+		fmt.Fprint(compressed, envelope)
+		compressed.Close()
+	})
+
+	for _, encoding := range []string{"gzip", "deflate", "identity"} {
+		price, err := MarketPrice(encoding, "btcusd")
+		if err != nil {
+			t.Fatalf("%s: %v", encoding, err)
+		}
+		if price != 42 {
+			t.Errorf("%s: price = %v, want 42", encoding, price)
+		}
+	}
+	for _, header := range accepted {
+		if header != "gzip, deflate" {
+			t.Errorf("Accept-Encoding = %q, want gzip, deflate", header)
+		}
+	}
+}
+
+func TestCompressedErrorResponses(t *testing.T) {
+	serve(t, func(w http.ResponseWriter, r *http.Request) {
+		// the exchange segment names the encoding claimed
+		w.Header().Set("Content-Encoding", strings.Split(r.URL.Path, "/")[2])
+		// the pair segment names the status and whether there is a plain body
+		switch strings.Split(r.URL.Path, "/")[3] {
+		case "empty":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "plain":
+			w.WriteHeader(http.StatusBadGateway)
+			fmt.Fprint(w, "<html><body>502 Bad Gateway</body></html>")
+		}
+	})
+
+	cases := []struct {
+		exchange, pair string
+		status         int
+	}{
+		{"gzip", "empty", http.StatusServiceUnavailable},
+		{"gzip", "plain", http.StatusBadGateway},
+		{"deflate", "plain", http.StatusBadGateway},
+	}
+	for _, c := range cases {
+		_, err := MarketPrice(c.exchange, c.pair)
+		var serverErr *ServerError
+		if !errors.As(err, &serverErr) || serverErr.StatusCode != c.status {
+			t.Errorf("%s %s: err = %v, want a ServerError with status %d", c.exchange, c.pair, err, c.status)
+		}
+	}
+}
+
 func TestAssets(t *testing.T) {
+	serveResults(t, map[string]string{
+		"/assets": `[
+			{"id":60,"symbol":"btc","name":"Bitcoin","fiat":false,"route":"https://api.cryptowat.ch/assets/btc"},
+			{"id":98,"symbol":"usd","name":"United States dollar","fiat":true,"route":"https://api.cryptowat.ch/assets/usd"},
+			{"symbol":"eth","name":"Ethereum","fiat":false,"route":"https://api.cryptowat.ch/assets/eth"},
+			{"id":9007199254740993,"symbol":"xyz","name":"Beyond float64","fiat":false}
+		]`,
+	})
+
+	assets, err := Assets()
+
+	if err != nil {
+		t.Fatal(err)
+	}
 
+	expected := []Asset{
+		{ID: 60, Symbol: "btc", Name: "Bitcoin", Route: "https://api.cryptowat.ch/assets/btc"},
+		{ID: 98, Symbol: "usd", Name: "United States dollar", Fiat: true, Route: "https://api.cryptowat.ch/assets/usd"},
+		{Symbol: "eth", Name: "Ethereum", Route: "https://api.cryptowat.ch/assets/eth"},
+		// ids past 2^53 only survive if the result is decoded straight into int
+		{ID: 9007199254740993, Symbol: "xyz", Name: "Beyond float64"},
+	}
+	if !reflect.DeepEqual(assets, expected) {
+		t.Errorf("got %+v, want %+v", assets, expected)
+	}
+}
+
+func TestMalformedResult(t *testing.T) {
+	serveResults(t, map[string]string{
+		"/assets":                       `{"symbol":"btc"}`,
+		"/pairs":                        `"pairs"`,
+		"/markets":                      `[{"exchange":42}]`,
+		"/markets/kraken/btcusd/price":  `{"price":"high"}`,
+		"/markets/kraken/btcusd/trades": `{"trades":[]}`,
+		"/markets/summaries":            `[]`,
+	})
+
+	calls := map[string]func() error{
+		"Assets": func() error {
+			_, err := Assets()
+			return err
+		},
+		"Pairs": func() error {
+			_, err := Pairs()
+			return err
+		},
+		"Markets": func() error {
+			_, err := Markets()
+			return err
+		},
+		"MarketPrice": func() error {
+			_, err := MarketPrice("kraken", "btcusd")
+			return err
+		},
+		"Trades": func() error {
+			_, err := Trades("kraken", "btcusd")
+			return err
+		},
+		"AggregrateSummaries": func() error {
+			_, err := AggregrateSummaries()
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		if err := call(); err == nil {
+			t.Errorf("%s: expected an error for a malformed result", name)
+		}
+	}
 }
 
 func TestAssetMarkets(t *testing.T) {
@@ -28,6 +303,41 @@ func TestExchange(t *testing.T) {
 
 }
 
+func TestExchangeMarkets(t *testing.T) {
+	url := serveResults(t, map[string]string{
+		"/markets/kraken": `[
+			{"exchange":"kraken","pair":"btcusd","active":true,"route":"https://api.cryptowat.ch/markets/kraken/btcusd"},
+			{"exchange":"kraken","pair":"ethbtc","active":false,"route":"https://api.cryptowat.ch/markets/kraken/ethbtc"}
+		]`,
+		"/markets/quiet":  `[]`,
+		"/markets/closed": `null`,
+	})
+
+	markets, err := ExchangeMarkets("kraken")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []GeneralMarket{
+		{Exchange: "kraken", Pair: "btcusd", Active: true, Route: "https://api.cryptowat.ch/markets/kraken/btcusd"},
+		{Exchange: "kraken", Pair: "ethbtc", Route: "https://api.cryptowat.ch/markets/kraken/ethbtc"},
+	}
+	if !reflect.DeepEqual(markets, expected) {
+		t.Errorf("got %+v, want %+v", markets, expected)
+	}
+
+	for _, exchange := range []string{"quiet", "closed"} {
+		if markets, err := ExchangeMarkets(exchange); err != nil || markets == nil || len(markets) != 0 {
+			t.Errorf("ExchangeMarkets(%q) = %#v, %v, want an empty slice", exchange, markets, err)
+		}
+	}
+
+	// retries wrap the error, even when the request was not retried
+	c := NewClient(WithBaseURL(url), WithRetry(3, time.Millisecond))
+	if markets, err := c.ExchangeMarkets("closed"); err != nil || markets == nil || len(markets) != 0 {
+		t.Errorf("ExchangeMarkets(%q) with retries = %#v, %v, want an empty slice", "closed", markets, err)
+	}
+}
+
 func TestMarkets(t *testing.T) {
 
 }
@@ -45,15 +355,127 @@ func TestMarketSummary(t *testing.T) {
 }
 
 func TestTrades(t *testing.T) {
+	var queries []string
+	serve(t, func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		fmt.Fprint(w, `{"result":[[0,1500000000,100.5,0.25],[0,1500000060,101,1.5]]}`)
+	})
+
+	trades, err := Trades("kraken", "btcusd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []Trade{{0, 1500000000, 100.5, 0.25}, {0, 1500000060, 101, 1.5}}; !reflect.DeepEqual(trades, expected) {
+		t.Errorf("got %v, want %v", trades, expected)
+	}
+
+	if _, err := TradesWithOptions("kraken", "btcusd", TradeOptions{Limit: 500, Since: 1500000000}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"", "limit=500&since=1500000000"}; !reflect.DeepEqual(queries, expected) {
+		t.Errorf("queries = %q, want %q", queries, expected)
+	}
 
+	if _, err := TradesWithOptions("kraken", "btcusd", TradeOptions{Limit: MaxTradesLimit + 1}); err == nil {
+		t.Error("expected an error for a limit above MaxTradesLimit")
+	}
+	if len(queries) != 2 {
+		t.Errorf("an invalid limit was sent to the api")
+	}
 }
 
 func TestOrderBook(t *testing.T) {
+	var queries []string
+	serve(t, func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		fmt.Fprint(w, `{"result":{"asks":[[101,1],[102,2]],"bids":[[100,3]]}}`)
+	})
 
+	orderbook, err := OrderBook("kraken", "btcusd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := MarketOrderBook{Asks: [][]float64{{101, 1}, {102, 2}}, Bids: [][]float64{{100, 3}}}
+	if !reflect.DeepEqual(orderbook, expected) {
+		t.Errorf("got %v, want %v", orderbook, expected)
+	}
+
+	options := []OrderBookOptions{
+		{Limit: 50, Span: 0.5},
+		{Depth: 12.5},
+		{},
+	}
+	for _, o := range options {
+		if _, err := OrderBookWithOptions("kraken", "btcusd", o); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if expected := []string{"", "limit=50&span=0.5", "depth=12.5", ""}; !reflect.DeepEqual(queries, expected) {
+		t.Errorf("queries = %q, want %q", queries, expected)
+	}
+
+	if _, err := OrderBookWithOptions("kraken", "btcusd", OrderBookOptions{Limit: -1}); err == nil {
+		t.Error("expected an error for a negative limit")
+	}
+}
+
+func BenchmarkOrderBook(b *testing.B) {
+	var payload strings.Builder
+	payload.WriteString(`{"result":{"asks":[`)
+	for i := 0; i < 5000; i++ {
+		if i > 0 {
+			payload.WriteString(",")
+		}
+		fmt.Fprintf(&payload, "[%.2f,%.8f]", 10000+float64(i)*0.5, 0.12345678)
+	}
+	payload.WriteString(`],"bids":[`)
+	for i := 0; i < 5000; i++ {
+		if i > 0 {
+			payload.WriteString(",")
+		}
+		fmt.Fprintf(&payload, "[%.2f,%.8f]", 9999-float64(i)*0.5, 0.12345678)
+	}
+	payload.WriteString(`]},"allowance":{"cost":0.005,"remaining":9.995}}`)
+	body := payload.String()
+
+	serve(b, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := OrderBook("kraken", "btcusd"); err != nil {
+			b.Fatal(err)
+		}
+	}
 }
 
 func TestOhlc(t *testing.T) {
+	var query string
+	serve(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		fmt.Fprint(w, `{"result":{"3600":[[1500010800,100,106,99,105,12]]}}`)
+	})
 
+	ohlc, err := Ohlc("kraken", "btcusd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query != "" {
+		t.Errorf("query = %q, want none", query)
+	}
+	if expected := (OHLC{"3600": {{1500010800, 100, 106, 99, 105, 12}}}); !reflect.DeepEqual(ohlc, expected) {
+		t.Errorf("got %v, want %v", ohlc, expected)
+	}
+
+	options := OhlcOptions{Periods: []string{"3600", "86400"}, After: 1500000000, Before: 1500086400}
+	if _, err := OhlcWithOptions("kraken", "btcusd", options); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "after=1500000000&before=1500086400&periods=3600%2C86400"; query != expected {
+		t.Errorf("query = %q, want %q", query, expected)
+	}
 }
 
 func TestAggregratePrices(t *testing.T) {
@@ -63,3 +485,34 @@ func TestAggregratePrices(t *testing.T) {
 func TestAggregrateSummaries(t *testing.T) {
 
 }
+
+func TestRaw(t *testing.T) {
+	var queries []string
+	serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/markets/kraken/btcusd/funding" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":"Route not found"}`)
+			return
+		}
+		queries = append(queries, r.URL.RawQuery)
+		fmt.Fprint(w, `{"result":{"rate":0.0001,"next":1500000000},"allowance":{"cost":0.001}}`)
+	})
+
+	for _, path := range []string{"markets/kraken/btcusd/funding?window=8h", "/markets/kraken/btcusd/funding"} {
+		res, err := Raw(context.Background(), path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(res) != `{"rate":0.0001,"next":1500000000}` {
+			t.Errorf("Raw(%q) = %s", path, res)
+		}
+	}
+	if expected := []string{"window=8h", ""}; !reflect.DeepEqual(queries, expected) {
+		t.Errorf("queries = %q, want %q", queries, expected)
+	}
+
+	var serverErr *ServerError
+	if _, err := Raw(context.Background(), "nowhere"); !errors.As(err, &serverErr) || serverErr.StatusCode != 404 {
+		t.Errorf("err = %v, want a 404 *ServerError", err)
+	}
+}
@@ -0,0 +1,103 @@
+// Package cryptowatchtest provides utilities for exercising code built on the
+// cryptowatch package. It is intended for use in tests only.
+package cryptowatchtest
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInjectedFault is returned for requests failed at the transport level.
+var ErrInjectedFault = errors.New("cryptowatchtest: injected fault")
+
+// FaultConfig describes the faults injected into requests
+type FaultConfig struct {
+	// Delay is added before every request is sent.
+	Delay time.Duration
+	// FailureRate is the fraction of requests, between 0 and 1, that fail.
+	FailureRate float64
+	// StatusCode is returned for failed requests. When zero, failed requests
+	// return ErrInjectedFault instead of a response.
+	StatusCode int
+	// Transport performs requests that are not failed. Defaults to
+	// http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+type faultTransport struct {
+	config FaultConfig
+	mu     sync.Mutex
+	rand   *rand.Rand
+}
+
+// WithFaultInjection returns a RoundTripper that delays and fails requests
+// according to config. Install it on the http.Client used by the code under
+// test, e.g. http.DefaultClient.Transport, to check how it copes with a slow or
+// flaky api without standing up a misbehaving server.
+func WithFaultInjection(config FaultConfig) http.RoundTripper {
+	if config.Transport == nil {
+		config.Transport = http.DefaultTransport
+	}
+	return &faultTransport{
+		config: config,
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (t *faultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.config.Delay > 0 {
+		timer := time.NewTimer(t.config.Delay)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			closeBody(req)
+			return nil, req.Context().Err()
+		}
+	}
+
+	if !t.fail() {
+		return t.config.Transport.RoundTrip(req)
+	}
+
+	// the inner transport is skipped, so the request body is closed here as it
+	// would have closed it
+	closeBody(req)
+
+	if t.config.StatusCode == 0 {
+		return nil, ErrInjectedFault
+	}
+
+	body := `{"error":"` + http.StatusText(t.config.StatusCode) + `"}`
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", t.config.StatusCode, http.StatusText(t.config.StatusCode)),
+		StatusCode:    t.config.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          ioutil.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// closeBody closes the request's body, if it has one, as a RoundTripper must.
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
+
+// fail decides whether the current request should fail.
+func (t *faultTransport) fail() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.rand.Float64() < t.config.FailureRate
+}
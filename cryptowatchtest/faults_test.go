@@ -0,0 +1,98 @@
+package cryptowatchtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithFaultInjection(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{}}`)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: WithFaultInjection(FaultConfig{})}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+
+	client.Transport = WithFaultInjection(FaultConfig{FailureRate: 1, StatusCode: http.StatusServiceUnavailable})
+	resp, err = client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", resp.StatusCode)
+	}
+	if resp.Status != "503 Service Unavailable" {
+		t.Errorf("status = %q, want %q", resp.Status, "503 Service Unavailable")
+	}
+
+	client.Transport = WithFaultInjection(FaultConfig{FailureRate: 1})
+	if _, err := client.Get(srv.URL); !errors.Is(err, ErrInjectedFault) {
+		t.Errorf("err = %v, want ErrInjectedFault", err)
+	}
+
+	delay := 20 * time.Millisecond
+	client.Transport = WithFaultInjection(FaultConfig{Delay: delay})
+	start := time.Now()
+	resp, err = client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if elapsed := time.Since(start); elapsed < delay {
+		t.Errorf("request took %v, want at least %v", elapsed, delay)
+	}
+}
+
+// closeRecorder is a request body that records whether it was closed.
+type closeRecorder struct {
+	*strings.Reader
+	closed bool
+}
+
+func (body *closeRecorder) Close() error {
+	body.closed = true
+	return nil
+}
+
+func TestFaultInjectionClosesBody(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	configs := map[string]struct {
+		config FaultConfig
+		ctx    context.Context
+	}{
+		"error":    {FaultConfig{FailureRate: 1}, context.Background()},
+		"status":   {FaultConfig{FailureRate: 1, StatusCode: http.StatusBadGateway}, context.Background()},
+		"canceled": {FaultConfig{Delay: time.Hour}, ctx},
+	}
+	for name, c := range configs {
+		body := &closeRecorder{Reader: strings.NewReader("{}")}
+		req, err := http.NewRequestWithContext(c.ctx, http.MethodPost, "http://example.invalid", body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp, _ := WithFaultInjection(c.config).RoundTrip(req); resp != nil {
+			resp.Body.Close()
+		}
+		if !body.closed {
+			t.Errorf("%s: request body was not closed", name)
+		}
+	}
+}
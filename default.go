@@ -0,0 +1,392 @@
+package cryptowatch
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Assets is a wrapper around DefaultClient.Assets.
+func Assets() ([]Asset, error) {
+	return DefaultClient.Assets()
+}
+
+// AssetsContext is a wrapper around DefaultClient.AssetsContext.
+func AssetsContext(ctx context.Context) ([]Asset, error) {
+	return DefaultClient.AssetsContext(ctx)
+}
+
+// AssetsPage is a wrapper around DefaultClient.AssetsPage.
+func AssetsPage(cursor string, limit int) ([]Asset, string, error) {
+	return DefaultClient.AssetsPage(cursor, limit)
+}
+
+// AssetsPageContext is a wrapper around DefaultClient.AssetsPageContext.
+func AssetsPageContext(ctx context.Context, cursor string, limit int) ([]Asset, string, error) {
+	return DefaultClient.AssetsPageContext(ctx, cursor, limit)
+}
+
+// AssetMarkets is a wrapper around DefaultClient.AssetMarkets.
+func AssetMarkets(asset string) (DetailedAsset, error) {
+	return DefaultClient.AssetMarkets(asset)
+}
+
+// AssetMarketsContext is a wrapper around DefaultClient.AssetMarketsContext.
+func AssetMarketsContext(ctx context.Context, asset string) (DetailedAsset, error) {
+	return DefaultClient.AssetMarketsContext(ctx, asset)
+}
+
+// Pairs is a wrapper around DefaultClient.Pairs.
+func Pairs() ([]Pair, error) {
+	return DefaultClient.Pairs()
+}
+
+// PairsContext is a wrapper around DefaultClient.PairsContext.
+func PairsContext(ctx context.Context) ([]Pair, error) {
+	return DefaultClient.PairsContext(ctx)
+}
+
+// PairsPage is a wrapper around DefaultClient.PairsPage.
+func PairsPage(cursor string, limit int) ([]Pair, string, error) {
+	return DefaultClient.PairsPage(cursor, limit)
+}
+
+// PairsPageContext is a wrapper around DefaultClient.PairsPageContext.
+func PairsPageContext(ctx context.Context, cursor string, limit int) ([]Pair, string, error) {
+	return DefaultClient.PairsPageContext(ctx, cursor, limit)
+}
+
+// PairMarkets is a wrapper around DefaultClient.PairMarkets.
+func PairMarkets(pair string) (PairMarket, error) {
+	return DefaultClient.PairMarkets(pair)
+}
+
+// PairMarketsContext is a wrapper around DefaultClient.PairMarketsContext.
+func PairMarketsContext(ctx context.Context, pair string) (PairMarket, error) {
+	return DefaultClient.PairMarketsContext(ctx, pair)
+}
+
+// Exchanges is a wrapper around DefaultClient.Exchanges.
+func Exchanges() ([]GeneralExchange, error) {
+	return DefaultClient.Exchanges()
+}
+
+// ExchangesContext is a wrapper around DefaultClient.ExchangesContext.
+func ExchangesContext(ctx context.Context) ([]GeneralExchange, error) {
+	return DefaultClient.ExchangesContext(ctx)
+}
+
+// ExchangesPage is a wrapper around DefaultClient.ExchangesPage.
+func ExchangesPage(cursor string, limit int) ([]GeneralExchange, string, error) {
+	return DefaultClient.ExchangesPage(cursor, limit)
+}
+
+// ExchangesPageContext is a wrapper around DefaultClient.ExchangesPageContext.
+func ExchangesPageContext(ctx context.Context, cursor string, limit int) ([]GeneralExchange, string, error) {
+	return DefaultClient.ExchangesPageContext(ctx, cursor, limit)
+}
+
+// Exchange is a wrapper around DefaultClient.Exchange.
+func Exchange(name string) (DetailedExchange, error) {
+	return DefaultClient.Exchange(name)
+}
+
+// ExchangeContext is a wrapper around DefaultClient.ExchangeContext.
+func ExchangeContext(ctx context.Context, name string) (DetailedExchange, error) {
+	return DefaultClient.ExchangeContext(ctx, name)
+}
+
+// ExchangeMarkets is a wrapper around DefaultClient.ExchangeMarkets.
+func ExchangeMarkets(exchange string) ([]GeneralMarket, error) {
+	return DefaultClient.ExchangeMarkets(exchange)
+}
+
+// ExchangeMarketsContext is a wrapper around DefaultClient.ExchangeMarketsContext.
+func ExchangeMarketsContext(ctx context.Context, exchange string) ([]GeneralMarket, error) {
+	return DefaultClient.ExchangeMarketsContext(ctx, exchange)
+}
+
+// Markets is a wrapper around DefaultClient.Markets.
+func Markets() ([]GeneralMarket, error) {
+	return DefaultClient.Markets()
+}
+
+// MarketsContext is a wrapper around DefaultClient.MarketsContext.
+func MarketsContext(ctx context.Context) ([]GeneralMarket, error) {
+	return DefaultClient.MarketsContext(ctx)
+}
+
+// MarketsPage is a wrapper around DefaultClient.MarketsPage.
+func MarketsPage(cursor string, limit int) ([]GeneralMarket, string, error) {
+	return DefaultClient.MarketsPage(cursor, limit)
+}
+
+// MarketsPageContext is a wrapper around DefaultClient.MarketsPageContext.
+func MarketsPageContext(ctx context.Context, cursor string, limit int) ([]GeneralMarket, string, error) {
+	return DefaultClient.MarketsPageContext(ctx, cursor, limit)
+}
+
+// Market is a wrapper around DefaultClient.Market.
+func Market(exchange, pair string) (DetailedMarket, error) {
+	return DefaultClient.Market(exchange, pair)
+}
+
+// MarketContext is a wrapper around DefaultClient.MarketContext.
+func MarketContext(ctx context.Context, exchange, pair string) (DetailedMarket, error) {
+	return DefaultClient.MarketContext(ctx, exchange, pair)
+}
+
+// MarketPrice is a wrapper around DefaultClient.MarketPrice.
+func MarketPrice(exchange, pair string) (float64, error) {
+	return DefaultClient.MarketPrice(exchange, pair)
+}
+
+// MarketPriceContext is a wrapper around DefaultClient.MarketPriceContext.
+func MarketPriceContext(ctx context.Context, exchange, pair string) (float64, error) {
+	return DefaultClient.MarketPriceContext(ctx, exchange, pair)
+}
+
+// MarketSummary is a wrapper around DefaultClient.MarketSummary.
+func MarketSummary(exchange, pair string) (Summary, error) {
+	return DefaultClient.MarketSummary(exchange, pair)
+}
+
+// MarketSummaryContext is a wrapper around DefaultClient.MarketSummaryContext.
+func MarketSummaryContext(ctx context.Context, exchange, pair string) (Summary, error) {
+	return DefaultClient.MarketSummaryContext(ctx, exchange, pair)
+}
+
+// Trades is a wrapper around DefaultClient.Trades.
+func Trades(exchange, pair string) ([]Trade, error) {
+	return DefaultClient.Trades(exchange, pair)
+}
+
+// TradesContext is a wrapper around DefaultClient.TradesContext.
+func TradesContext(ctx context.Context, exchange, pair string) ([]Trade, error) {
+	return DefaultClient.TradesContext(ctx, exchange, pair)
+}
+
+// TradesWithOptions is a wrapper around DefaultClient.TradesWithOptions.
+func TradesWithOptions(exchange, pair string, options TradeOptions) ([]Trade, error) {
+	return DefaultClient.TradesWithOptions(exchange, pair, options)
+}
+
+// TradesWithOptionsContext is a wrapper around DefaultClient.TradesWithOptionsContext.
+func TradesWithOptionsContext(ctx context.Context, exchange, pair string, options TradeOptions) ([]Trade, error) {
+	return DefaultClient.TradesWithOptionsContext(ctx, exchange, pair, options)
+}
+
+// OrderBook is a wrapper around DefaultClient.OrderBook.
+func OrderBook(exchange, pair string) (MarketOrderBook, error) {
+	return DefaultClient.OrderBook(exchange, pair)
+}
+
+// OrderBookContext is a wrapper around DefaultClient.OrderBookContext.
+func OrderBookContext(ctx context.Context, exchange, pair string) (MarketOrderBook, error) {
+	return DefaultClient.OrderBookContext(ctx, exchange, pair)
+}
+
+// OrderBookWithOptions is a wrapper around DefaultClient.OrderBookWithOptions.
+func OrderBookWithOptions(exchange, pair string, options OrderBookOptions) (MarketOrderBook, error) {
+	return DefaultClient.OrderBookWithOptions(exchange, pair, options)
+}
+
+// OrderBookWithOptionsContext is a wrapper around DefaultClient.OrderBookWithOptionsContext.
+func OrderBookWithOptionsContext(ctx context.Context, exchange, pair string, options OrderBookOptions) (MarketOrderBook, error) {
+	return DefaultClient.OrderBookWithOptionsContext(ctx, exchange, pair, options)
+}
+
+// Ohlc is a wrapper around DefaultClient.Ohlc.
+func Ohlc(exchange, pair string) (OHLC, error) {
+	return DefaultClient.Ohlc(exchange, pair)
+}
+
+// OhlcContext is a wrapper around DefaultClient.OhlcContext.
+func OhlcContext(ctx context.Context, exchange, pair string) (OHLC, error) {
+	return DefaultClient.OhlcContext(ctx, exchange, pair)
+}
+
+// OhlcWithOptions is a wrapper around DefaultClient.OhlcWithOptions.
+func OhlcWithOptions(exchange, pair string, options OhlcOptions) (OHLC, error) {
+	return DefaultClient.OhlcWithOptions(exchange, pair, options)
+}
+
+// OhlcWithOptionsContext is a wrapper around DefaultClient.OhlcWithOptionsContext.
+func OhlcWithOptionsContext(ctx context.Context, exchange, pair string, options OhlcOptions) (OHLC, error) {
+	return DefaultClient.OhlcWithOptionsContext(ctx, exchange, pair, options)
+}
+
+// OhlcPeriod is a wrapper around DefaultClient.OhlcPeriod.
+func OhlcPeriod(exchange, pair, period string) ([]Candle, error) {
+	return DefaultClient.OhlcPeriod(exchange, pair, period)
+}
+
+// OhlcPeriodContext is a wrapper around DefaultClient.OhlcPeriodContext.
+func OhlcPeriodContext(ctx context.Context, exchange, pair, period string) ([]Candle, error) {
+	return DefaultClient.OhlcPeriodContext(ctx, exchange, pair, period)
+}
+
+// AggregratePrices is a wrapper around DefaultClient.AggregratePrices.
+func AggregratePrices() (AggregratePrice, error) {
+	return DefaultClient.AggregratePrices()
+}
+
+// AggregratePricesContext is a wrapper around DefaultClient.AggregratePricesContext.
+func AggregratePricesContext(ctx context.Context) (AggregratePrice, error) {
+	return DefaultClient.AggregratePricesContext(ctx)
+}
+
+// AggregrateSummaries is a wrapper around DefaultClient.AggregrateSummaries.
+func AggregrateSummaries() (AggregrateSummary, error) {
+	return DefaultClient.AggregrateSummaries()
+}
+
+// AggregrateSummariesContext is a wrapper around DefaultClient.AggregrateSummariesContext.
+func AggregrateSummariesContext(ctx context.Context) (AggregrateSummary, error) {
+	return DefaultClient.AggregrateSummariesContext(ctx)
+}
+
+// Raw is a wrapper around DefaultClient.Raw.
+func Raw(ctx context.Context, path string) (json.RawMessage, error) {
+	return DefaultClient.Raw(ctx, path)
+}
+
+// ExchangesByMarketCount is a wrapper around DefaultClient.ExchangesByMarketCount.
+func ExchangesByMarketCount() ([]ExchangeRank, error) {
+	return DefaultClient.ExchangesByMarketCount()
+}
+
+// ExchangePairCounts is a wrapper around DefaultClient.ExchangePairCounts.
+func ExchangePairCounts() (map[string]int, error) {
+	return DefaultClient.ExchangePairCounts()
+}
+
+// WatchImbalance is a wrapper around DefaultClient.WatchImbalance.
+func WatchImbalance(ctx context.Context, exchange, pair string, levels int, interval time.Duration) (<-chan float64, error) {
+	return DefaultClient.WatchImbalance(ctx, exchange, pair, levels, interval)
+}
+
+// BookResilience is a wrapper around DefaultClient.BookResilience.
+func BookResilience(ctx context.Context, exchange, pair string, samples int, interval time.Duration) (float64, error) {
+	return DefaultClient.BookResilience(ctx, exchange, pair, samples, interval)
+}
+
+// RollingVWAP is a wrapper around DefaultClient.RollingVWAP.
+func RollingVWAP(ctx context.Context, exchange, pair string, window time.Duration, interval time.Duration) (<-chan float64, error) {
+	return DefaultClient.RollingVWAP(ctx, exchange, pair, window, interval)
+}
+
+// WideSpreadMarkets is a wrapper around DefaultClient.WideSpreadMarkets.
+func WideSpreadMarkets(ctx context.Context, pair string, thresholdBps float64) ([]MarketSpread, error) {
+	return DefaultClient.WideSpreadMarkets(ctx, pair, thresholdBps)
+}
+
+// AssetMarketPrices is a wrapper around DefaultClient.AssetMarketPrices.
+func AssetMarketPrices(ctx context.Context, symbol string) (map[MarketRef]float64, error) {
+	return DefaultClient.AssetMarketPrices(ctx, symbol)
+}
+
+// ScanCrossedBooks is a wrapper around DefaultClient.ScanCrossedBooks.
+func ScanCrossedBooks(ctx context.Context, refs []MarketRef) ([]MarketRef, error) {
+	return DefaultClient.ScanCrossedBooks(ctx, refs)
+}
+
+// MarketsByLiquidity is a wrapper around DefaultClient.MarketsByLiquidity.
+func MarketsByLiquidity(ctx context.Context, pair string, depthPct float64) ([]MarketLiquidity, error) {
+	return DefaultClient.MarketsByLiquidity(ctx, pair, depthPct)
+}
+
+// PriceOutliers is a wrapper around DefaultClient.PriceOutliers.
+func PriceOutliers(ctx context.Context, pair string, thresholdPct float64) ([]PriceOutlier, error) {
+	return DefaultClient.PriceOutliers(ctx, pair, thresholdPct)
+}
+
+// CanonicalExchange is a wrapper around DefaultClient.CanonicalExchange.
+func CanonicalExchange(name string) (string, error) {
+	return DefaultClient.CanonicalExchange(name)
+}
+
+// ValidatePair is a wrapper around DefaultClient.ValidatePair.
+func ValidatePair(ctx context.Context, pair string) (base, quote Asset, err error) {
+	return DefaultClient.ValidatePair(ctx, pair)
+}
+
+// PairsForAssetOnExchange is a wrapper around DefaultClient.PairsForAssetOnExchange.
+func PairsForAssetOnExchange(ctx context.Context, asset, exchange string) ([]string, error) {
+	return DefaultClient.PairsForAssetOnExchange(ctx, asset, exchange)
+}
+
+// PairSymbol is a wrapper around DefaultClient.PairSymbol.
+func PairSymbol(base, quote string) (string, error) {
+	return DefaultClient.PairSymbol(base, quote)
+}
+
+// PairIDMap is a wrapper around DefaultClient.PairIDMap.
+func PairIDMap() (map[string]int, map[int]string, error) {
+	return DefaultClient.PairIDMap()
+}
+
+// PriceChangeOver is a wrapper around DefaultClient.PriceChangeOver.
+func PriceChangeOver(ctx context.Context, exchange, pair string, lookback time.Duration) (pct float64, from, to float64, err error) {
+	return DefaultClient.PriceChangeOver(ctx, exchange, pair, lookback)
+}
+
+// MarketsFiltered is a wrapper around DefaultClient.MarketsFiltered.
+func MarketsFiltered(ctx context.Context, filter MarketFilter) ([]GeneralMarket, error) {
+	return DefaultClient.MarketsFiltered(ctx, filter)
+}
+
+// FiatMarketsForAsset is a wrapper around DefaultClient.FiatMarketsForAsset.
+func FiatMarketsForAsset(ctx context.Context, symbol string) ([]string, error) {
+	return DefaultClient.FiatMarketsForAsset(ctx, symbol)
+}
+
+// SummaryWithFallback is a wrapper around DefaultClient.SummaryWithFallback.
+func SummaryWithFallback(ctx context.Context, pair string, preferred []string) (Summary, string, error) {
+	return DefaultClient.SummaryWithFallback(ctx, pair, preferred)
+}
+
+// AllAssets is a wrapper around DefaultClient.AllAssets.
+func AllAssets(ctx context.Context) ([]Asset, error) {
+	return DefaultClient.AllAssets(ctx)
+}
+
+// AllPairs is a wrapper around DefaultClient.AllPairs.
+func AllPairs(ctx context.Context) ([]Pair, error) {
+	return DefaultClient.AllPairs(ctx)
+}
+
+// AllExchanges is a wrapper around DefaultClient.AllExchanges.
+func AllExchanges(ctx context.Context) ([]GeneralExchange, error) {
+	return DefaultClient.AllExchanges(ctx)
+}
+
+// AllMarkets is a wrapper around DefaultClient.AllMarkets.
+func AllMarkets(ctx context.Context) ([]GeneralMarket, error) {
+	return DefaultClient.AllMarkets(ctx)
+}
+
+// SummariesForExchange is a wrapper around DefaultClient.SummariesForExchange.
+func SummariesForExchange(exchange string) (map[string]Summary, error) {
+	return DefaultClient.SummariesForExchange(exchange)
+}
+
+// SummariesForExchangeContext is a wrapper around DefaultClient.SummariesForExchangeContext.
+func SummariesForExchangeContext(ctx context.Context, exchange string) (map[string]Summary, error) {
+	return DefaultClient.SummariesForExchangeContext(ctx, exchange)
+}
+
+// Summaries is a wrapper around DefaultClient.Summaries.
+func Summaries(ctx context.Context, markets []MarketRef) (map[MarketRef]Summary, error) {
+	return DefaultClient.Summaries(ctx, markets)
+}
+
+// MarketActive is a wrapper around DefaultClient.MarketActive.
+func MarketActive(exchange, pair string) (bool, error) {
+	return DefaultClient.MarketActive(exchange, pair)
+}
+
+// MarketActiveContext is a wrapper around DefaultClient.MarketActiveContext.
+func MarketActiveContext(ctx context.Context, exchange, pair string) (bool, error) {
+	return DefaultClient.MarketActiveContext(ctx, exchange, pair)
+}
@@ -0,0 +1,114 @@
+package cryptowatch
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrNoResult is returned when a successful response carries no result field at
+// all.
+var ErrNoResult = errors.New("response contains no result")
+
+// ErrEmptyResult is returned when a successful response carries a result that
+// is null.
+var ErrEmptyResult = errors.New("response result is null")
+
+// ErrMarketNotFound is returned, wrapped, by MarketActive when the api does not
+// know the market. Check for it with errors.Is.
+var ErrMarketNotFound = errors.New("market not found")
+
+// AuthError is returned when the api rejects a request's credentials, either
+// because the api key is invalid (401) or lacks permission (403).
+type AuthError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("authentication failed (%d): %s", e.StatusCode, e.Message)
+}
+
+// maxSnippet is the length of the body excerpt included in unexpected response
+// errors.
+const maxSnippet = 128
+
+// snippet returns body cut to maxSnippet bytes, marked when it was cut.
+func snippet(body []byte) string {
+	if len(body) > maxSnippet {
+		return string(body[:maxSnippet]) + "..."
+	}
+	return string(body)
+}
+
+// unexpectedResponse reports a body that is not a json object, such as a proxy's
+// html error page, with an excerpt of the body.
+func unexpectedResponse(status int, body []byte) error {
+	return fmt.Errorf("unexpected response (status %d): %q", status, snippet(body))
+}
+
+// ServerError is returned when the api answers with any other unsuccessful
+// status, such as a 404 for an unknown market or a 500. Endpoint is the path of
+// the request relative to the base url, Message the api's error message if it
+// sent one and Body an excerpt of the raw body.
+type ServerError struct {
+	StatusCode int
+	Endpoint   string
+	Message    string
+	Body       string
+}
+
+func (e *ServerError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s: %d %s: %s", e.Endpoint, e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+	}
+	return fmt.Sprintf("%s: %d %s: %q", e.Endpoint, e.StatusCode, http.StatusText(e.StatusCode), e.Body)
+}
+
+// RateLimitError is returned when the api throttles a request (429). RetryAfter
+// comes from the Retry-After header when present and is otherwise estimated from
+// the hourly allowance reset.
+type RateLimitError struct {
+	RetryAfter time.Duration
+	Message    string
+}
+
+func (e *RateLimitError) Error() string {
+	return e.Message
+}
+
+// RetryError is returned by a Client with retries enabled when a request fails
+// for good. Err is the failure of the last attempt.
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("request failed after %d attempts: %v", e.Attempts, e.Err)
+}
+
+// Unwrap returns the failure of the last attempt.
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// parseRetryAfter reads a Retry-After header given either in seconds or as an
+// http date.
+func parseRetryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait.Round(time.Second), true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,163 @@
+package cryptowatch
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuthError(t *testing.T) {
+	for _, status := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			serve(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(status)
+				fmt.Fprint(w, `{"error":"Invalid API key"}`)
+			})
+
+			_, err := MarketPrice("kraken", "btcusd")
+
+			var authErr *AuthError
+			if !errors.As(err, &authErr) {
+				t.Fatalf("err = %v, want an *AuthError", err)
+			}
+			if authErr.StatusCode != status || authErr.Message != "Invalid API key" {
+				t.Errorf("got %+v", authErr)
+			}
+		})
+	}
+}
+
+func TestErrNoResult(t *testing.T) {
+	serve(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"allowance":{"cost":1,"remaining":7999}}`)
+	})
+
+	if _, err := Markets(); err != ErrNoResult {
+		t.Errorf("err = %v, want ErrNoResult", err)
+	}
+}
+
+func TestErrEmptyResult(t *testing.T) {
+	serve(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":null,"error":""}`)
+	})
+
+	if _, err := AggregrateSummaries(); err != ErrEmptyResult {
+		t.Errorf("err = %v, want ErrEmptyResult", err)
+	}
+}
+
+func TestTooManyRequests(t *testing.T) {
+	serve(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"error":"Out of allowance"}`)
+	})
+
+	before := 60 - time.Now().Minute()
+	_, err := Markets()
+	after := 60 - time.Now().Minute()
+
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("err = %v, want a *RateLimitError", err)
+	}
+	if !strings.Contains(err.Error(), " "+strconv.Itoa(before)+" minutes") &&
+		!strings.Contains(err.Error(), " "+strconv.Itoa(after)+" minutes") {
+		t.Errorf("err = %q, want the minutes until the allowance resets", err)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	cases := map[string]time.Duration{
+		"30": 30 * time.Second,
+		time.Now().Add(2 * time.Minute).UTC().Format(http.TimeFormat): 2 * time.Minute,
+	}
+
+	for header, expected := range cases {
+		serve(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", header)
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"error":"Out of allowance"}`)
+		})
+
+		_, err := Markets()
+
+		var rateErr *RateLimitError
+		if !errors.As(err, &rateErr) {
+			t.Fatalf("err = %v, want a *RateLimitError", err)
+		}
+		if diff := rateErr.RetryAfter - expected; diff < -time.Second || diff > time.Second {
+			t.Errorf("Retry-After %q gave %v, want %v", header, rateErr.RetryAfter, expected)
+		}
+	}
+}
+
+func TestUnexpectedResponse(t *testing.T) {
+	bodies := map[string]int{
+		`<html>503</html>`:        http.StatusServiceUnavailable,
+		`["not","an","object"]`:   http.StatusOK,
+		strings.Repeat("x", 1000): http.StatusBadGateway,
+		`{"result":[1,2,3]`:       http.StatusOK,
+	}
+
+	for body, status := range bodies {
+		serve(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			fmt.Fprint(w, body)
+		})
+
+		_, err := Markets()
+
+		if err == nil || !strings.Contains(err.Error(), strconv.Itoa(status)) {
+			t.Errorf("body %.20q: err = %v, want an error with the status", body, err)
+			continue
+		}
+		if len(err.Error()) > 2*maxSnippet {
+			t.Errorf("body %.20q: error is not truncated: %d bytes", body, len(err.Error()))
+		}
+	}
+}
+
+func TestServerError(t *testing.T) {
+	serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/markets/mtgox/btcusd/price" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":"Route not found"}`)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	cases := []struct {
+		call     func() error
+		expected ServerError
+	}{
+		{
+			func() error { _, err := MarketPrice("mtgox", "btcusd"); return err },
+			ServerError{StatusCode: 404, Endpoint: "markets/mtgox/btcusd/price", Message: "Route not found", Body: `{"error":"Route not found"}`},
+		},
+		{
+			func() error { _, err := Markets(); return err },
+			ServerError{StatusCode: 500, Endpoint: "markets"},
+		},
+	}
+	for _, c := range cases {
+		err := c.call()
+
+		var serverErr *ServerError
+		if !errors.As(err, &serverErr) {
+			t.Errorf("err = %v, want a *ServerError", err)
+			continue
+		}
+		if *serverErr != c.expected {
+			t.Errorf("got %+v, want %+v", *serverErr, c.expected)
+		}
+		if !strings.Contains(err.Error(), strconv.Itoa(c.expected.StatusCode)) || !strings.Contains(err.Error(), c.expected.Endpoint) {
+			t.Errorf("err = %q, want the status and endpoint", err)
+		}
+	}
+}
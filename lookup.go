@@ -0,0 +1,299 @@
+package cryptowatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// listingCache holds the rarely changing listings used by the lookup helpers.
+// Each listing is fetched on first use and kept for the life of the client.
+type listingCache struct {
+	exchangeSymbols listing
+	assets          listing
+	pairs           listing
+}
+
+// listing is one cached listing. It is fetched by one caller at a time, without
+// holding the lock, so a slow fetch of one listing does not hold up lookups in
+// another and waiting callers can give up when their ctx is done.
+type listing struct {
+	mu      sync.Mutex
+	value   interface{}
+	filled  bool
+	filling chan struct{}
+}
+
+// get returns the listing, calling fill to fetch it if it is not cached yet.
+// When another caller is already fetching it, get waits for that fetch and
+// fetches it itself if that one failed.
+func (l *listing) get(ctx context.Context, fill func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	for {
+		l.mu.Lock()
+		if l.filled {
+			value := l.value
+			l.mu.Unlock()
+			return value, nil
+		}
+
+		if l.filling == nil {
+			done := make(chan struct{})
+			l.filling = done
+			l.mu.Unlock()
+
+			value, err := fill(ctx)
+
+			l.mu.Lock()
+			if err == nil {
+				l.value, l.filled = value, true
+			}
+			l.filling = nil
+			l.mu.Unlock()
+			close(done)
+			return value, err
+		}
+
+		filling := l.filling
+		l.mu.Unlock()
+
+		select {
+		case <-filling:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// cachedAssets returns the assets listing keyed by symbol.
+func (c *Client) cachedAssets(ctx context.Context) (map[string]Asset, error) {
+	value, err := c.listings.assets.get(ctx, func(ctx context.Context) (interface{}, error) {
+		assets, err := c.AllAssets(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		bySymbol := make(map[string]Asset, len(assets))
+		for _, asset := range assets {
+			bySymbol[asset.Symbol] = asset
+		}
+		return bySymbol, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return value.(map[string]Asset), nil
+}
+
+// cachedPairs returns the pairs listing.
+func (c *Client) cachedPairs(ctx context.Context) ([]Pair, error) {
+	value, err := c.listings.pairs.get(ctx, func(ctx context.Context) (interface{}, error) {
+		return c.AllPairs(ctx)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return value.([]Pair), nil
+}
+
+// CanonicalExchange resolves an exchange symbol or name, in any casing, to the
+// canonical symbol the api uses, e.g. "Kraken" to "kraken". The exchanges
+// listing is fetched once and cached for the life of the client.
+func (c *Client) CanonicalExchange(name string) (string, error) {
+	return c.canonicalExchange(context.Background(), name)
+}
+
+func (c *Client) canonicalExchange(ctx context.Context, name string) (string, error) {
+	value, err := c.listings.exchangeSymbols.get(ctx, func(ctx context.Context) (interface{}, error) {
+		exchanges, err := c.AllExchanges(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		symbols := make(map[string]string, 2*len(exchanges))
+		for _, exchange := range exchanges {
+			symbols[strings.ToLower(exchange.Name)] = exchange.Symbol
+		}
+		for _, exchange := range exchanges {
+			symbols[strings.ToLower(exchange.Symbol)] = exchange.Symbol
+		}
+		return symbols, nil
+	})
+
+	if err != nil {
+		return "", err
+	}
+	if symbol, ok := value.(map[string]string)[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return symbol, nil
+	}
+	return "", fmt.Errorf("unknown exchange %q", name)
+}
+
+// ValidatePair resolves a pair and checks that both its base and quote assets
+// exist in the assets listing, returning them. The assets listing is cached.
+func (c *Client) ValidatePair(ctx context.Context, pair string) (base, quote Asset, err error) {
+	resolved, err := c.PairMarketsContext(ctx, pair)
+
+	if err != nil {
+		return base, quote, err
+	}
+
+	assets, err := c.cachedAssets(ctx)
+
+	if err != nil {
+		return base, quote, err
+	}
+
+	base, ok := assets[resolved.Base.Symbol]
+	if !ok {
+		return base, quote, fmt.Errorf("pair %s: base asset %q does not exist", pair, resolved.Base.Symbol)
+	}
+	quote, ok = assets[resolved.Quote.Symbol]
+	if !ok {
+		return base, quote, fmt.Errorf("pair %s: quote asset %q does not exist", pair, resolved.Quote.Symbol)
+	}
+
+	return base, quote, nil
+}
+
+// PairsForAssetOnExchange returns the symbols of the pairs on exchange that have
+// asset as their base or quote, sorted. The exchange may be given by symbol or
+// name in any casing and must exist in the exchanges listing.
+func (c *Client) PairsForAssetOnExchange(ctx context.Context, asset, exchange string) ([]string, error) {
+	symbol, err := c.canonicalExchange(ctx, exchange)
+
+	if err != nil {
+		return nil, err
+	}
+
+	detailed, err := c.AssetMarketsContext(ctx, asset)
+
+	if err != nil {
+		return nil, fmt.Errorf("asset %s: %v", asset, err)
+	}
+
+	seen := make(map[string]bool)
+	pairs := []string{}
+	for _, markets := range [][]AssetMarket{detailed.Markets.Base, detailed.Markets.Quote} {
+		for _, market := range markets {
+			if market.Exchange == symbol && !seen[market.Pair] {
+				seen[market.Pair] = true
+				pairs = append(pairs, market.Pair)
+			}
+		}
+	}
+
+	sort.Strings(pairs)
+	return pairs, nil
+}
+
+// PairSymbol returns the symbol of the pair with the given base and quote asset
+// symbols, e.g. "btc" and "usd" to "btcusd". Symbols match in any casing. The
+// pairs listing is fetched once and cached for the life of the client.
+func (c *Client) PairSymbol(base, quote string) (string, error) {
+	pairs, err := c.cachedPairs(context.Background())
+
+	if err != nil {
+		return "", err
+	}
+
+	for _, pair := range pairs {
+		if strings.EqualFold(pair.Base.Symbol, base) && strings.EqualFold(pair.Quote.Symbol, quote) {
+			return pair.Symbol, nil
+		}
+	}
+	return "", fmt.Errorf("no pair with base %q and quote %q", base, quote)
+}
+
+// PairIDMap returns the pairs listing as lookups from symbol to ID and from ID to
+// symbol. Should a symbol be listed more than once, every ID maps back to it but
+// the symbol maps to the lowest of its IDs. The pairs listing is fetched once and
+// cached for the life of the client.
+func (c *Client) PairIDMap() (map[string]int, map[int]string, error) {
+	pairs, err := c.cachedPairs(context.Background())
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids := make(map[string]int, len(pairs))
+	symbols := make(map[int]string, len(pairs))
+	for _, pair := range pairs {
+		symbols[pair.ID] = pair.Symbol
+		if id, ok := ids[pair.Symbol]; !ok || pair.ID < id {
+			ids[pair.Symbol] = pair.ID
+		}
+	}
+
+	return ids, symbols, nil
+}
+
+// FiatMarketsForAsset returns the sorted symbols of the fiat currencies that
+// symbol is quoted in on any market. Quote assets are resolved through the pairs
+// listing and checked against the Fiat flag of the assets listing, both of which
+// are fetched once and cached for the life of the client.
+func (c *Client) FiatMarketsForAsset(ctx context.Context, symbol string) ([]string, error) {
+	detailed, err := c.AssetMarketsContext(ctx, symbol)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pairs, err := c.cachedPairs(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	assets, err := c.cachedAssets(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	quotes := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		quotes[pair.Symbol] = pair.Quote.Symbol
+	}
+
+	seen := make(map[string]bool)
+	fiats := []string{}
+	for _, market := range detailed.Markets.Base {
+		quote, ok := quotes[market.Pair]
+		if ok && assets[quote].Fiat && !seen[quote] {
+			seen[quote] = true
+			fiats = append(fiats, quote)
+		}
+	}
+
+	sort.Strings(fiats)
+	return fiats, nil
+}
+
+// MarketActive reports whether a market exists and is active. A market the api
+// does not know fails with an error wrapping ErrMarketNotFound.
+func (c *Client) MarketActive(exchange, pair string) (bool, error) {
+	return c.MarketActiveContext(context.Background(), exchange, pair)
+}
+
+// MarketActiveContext is like MarketActive but aborts the request when ctx is
+// done.
+func (c *Client) MarketActiveContext(ctx context.Context, exchange, pair string) (bool, error) {
+	market, err := c.MarketContext(ctx, exchange, pair)
+
+	var serverErr *ServerError
+	if errors.As(err, &serverErr) && serverErr.StatusCode == http.StatusNotFound {
+		return false, fmt.Errorf("%s:%s: %w", exchange, pair, ErrMarketNotFound)
+	}
+	if err != nil {
+		return false, err
+	}
+	return market.Active, nil
+}
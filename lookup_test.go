@@ -0,0 +1,277 @@
+package cryptowatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCanonicalExchange(t *testing.T) {
+	listings := 0
+	serve(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/exchanges":
+			listings++
+			fmt.Fprint(w, `{"result":[
+				{"symbol":"kraken","name":"Kraken","active":true},
+				{"symbol":"coinbase-pro","name":"Coinbase Pro","active":true}
+			]}`)
+		case "/markets/summaries":
+			fmt.Fprint(w, `{"result":{
+				"Kraken:btcusd":{"price":{"last":100},"volume":5},
+				"COINBASE-PRO:btcusd":{"price":{"last":101},"volume":7}
+			}}`)
+		}
+	})
+
+	summaries, err := AggregrateSummaries()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exchanges, err := Exchanges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	byExchange := make(map[string]GeneralExchange)
+	for _, exchange := range exchanges {
+		byExchange[exchange.Symbol] = exchange
+	}
+
+	for key := range summaries {
+		symbol, err := CanonicalExchange(strings.SplitN(key, ":", 2)[0])
+		if err != nil {
+			t.Fatalf("CanonicalExchange(%q): %v", key, err)
+		}
+		if _, ok := byExchange[symbol]; !ok {
+			t.Errorf("summary %q did not join to an exchange (resolved %q)", key, symbol)
+		}
+	}
+
+	if symbol, err := CanonicalExchange("coinbase pro"); err != nil || symbol != "coinbase-pro" {
+		t.Errorf("CanonicalExchange by name = %q, %v, want coinbase-pro", symbol, err)
+	}
+	if _, err := CanonicalExchange("mtgox"); err == nil {
+		t.Error("expected an error for an unknown exchange")
+	}
+	if listings != 2 {
+		t.Errorf("exchanges listed %d times, want 2 (once for the join, once for the cache)", listings)
+	}
+}
+
+func TestListingsFetchIndependently(t *testing.T) {
+	arrived := make(chan struct{})
+	release := make(chan struct{})
+	serve(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/assets":
+			arrived <- struct{}{}
+			<-release
+			fmt.Fprint(w, `{"result":[{"symbol":"btc"}]}`)
+		case "/exchanges":
+			fmt.Fprint(w, `{"result":[{"symbol":"kraken","name":"Kraken"}]}`)
+		}
+	})
+
+	fetched := make(chan error)
+	go func() {
+		_, err := DefaultClient.cachedAssets(context.Background())
+		fetched <- err
+	}()
+	<-arrived
+
+	if symbol, err := CanonicalExchange("Kraken"); err != nil || symbol != "kraken" {
+		t.Errorf("CanonicalExchange during an assets fetch = %q, %v, want kraken", symbol, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := DefaultClient.cachedAssets(ctx); err != context.Canceled {
+		t.Errorf("waiting with a cancelled ctx: err = %v, want context.Canceled", err)
+	}
+
+	close(release)
+	if err := <-fetched; err != nil {
+		t.Fatal(err)
+	}
+	if assets, err := DefaultClient.cachedAssets(context.Background()); err != nil || len(assets) != 1 {
+		t.Errorf("cached assets = %v, %v", assets, err)
+	}
+}
+
+func TestValidatePair(t *testing.T) {
+	serveResults(t, map[string]string{
+		"/assets": `[
+			{"id":60,"symbol":"btc","name":"Bitcoin","fiat":false},
+			{"id":98,"symbol":"usd","name":"United States dollar","fiat":true}
+		]`,
+		"/pairs/btcusd": `{"symbol":"btcusd","base":{"symbol":"btc"},"quote":{"symbol":"usd"}}`,
+		"/pairs/ethusd": `{"symbol":"ethusd","base":{"symbol":"eth"},"quote":{"symbol":"usd"}}`,
+	})
+
+	base, quote, err := ValidatePair(context.Background(), "btcusd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if base.ID != 60 || quote.ID != 98 {
+		t.Errorf("got base %+v and quote %+v", base, quote)
+	}
+
+	_, _, err = ValidatePair(context.Background(), "ethusd")
+	if err == nil || !strings.Contains(err.Error(), `base asset "eth"`) {
+		t.Errorf("err = %v, want a missing base asset error", err)
+	}
+
+	if _, _, err = ValidatePair(context.Background(), "xrpusd"); err == nil {
+		t.Error("expected an error for an unknown pair")
+	}
+}
+
+func TestPairsForAssetOnExchange(t *testing.T) {
+	serveResults(t, map[string]string{
+		"/exchanges": `[
+			{"symbol":"kraken","name":"Kraken","active":true},
+			{"symbol":"gdax","name":"GDAX","active":true}
+		]`,
+		"/assets/eth": `{"id":77,"symbol":"eth","name":"Ethereum","fiat":false,"markets":{
+			"base":[
+				{"exchange":"kraken","pair":"etheur","active":true},
+				{"exchange":"gdax","pair":"ethusd","active":true},
+				{"exchange":"kraken","pair":"ethbtc","active":true}
+			],
+			"quote":[
+				{"exchange":"kraken","pair":"etceth","active":true},
+				{"exchange":"gdax","pair":"etceth","active":true}
+			]
+		}}`,
+	})
+
+	pairs, err := PairsForAssetOnExchange(context.Background(), "eth", "Kraken")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"etceth", "ethbtc", "etheur"}; !reflect.DeepEqual(pairs, expected) {
+		t.Errorf("pairs = %v, want %v", pairs, expected)
+	}
+
+	if _, err := PairsForAssetOnExchange(context.Background(), "eth", "mtgox"); err == nil {
+		t.Error("expected an error for an unknown exchange")
+	}
+	if _, err := PairsForAssetOnExchange(context.Background(), "doge", "kraken"); err == nil {
+		t.Error("expected an error for an unknown asset")
+	}
+}
+
+func TestPairSymbol(t *testing.T) {
+	serveResults(t, map[string]string{
+		"/pairs": `[
+			{"symbol":"btcusd","id":9,"base":{"symbol":"btc","name":"Bitcoin"},"quote":{"symbol":"usd","name":"United States dollar","isFiat":true}},
+			{"symbol":"ethbtc","id":45,"base":{"symbol":"eth","name":"Ethereum"},"quote":{"symbol":"btc","name":"Bitcoin"}},
+			{"symbol":"bchusd","id":1203,"base":{"symbol":"bch","name":"Bitcoin Cash"},"quote":{"symbol":"usd","name":"United States dollar","isFiat":true}}
+		]`,
+	})
+
+	if symbol, err := PairSymbol("ETH", "btc"); err != nil || symbol != "ethbtc" {
+		t.Errorf("PairSymbol(ETH, btc) = %q, %v, want ethbtc", symbol, err)
+	}
+	if _, err := PairSymbol("btc", "eth"); err == nil {
+		t.Error("expected an error for an inverted pair")
+	}
+}
+
+func TestPairIDMap(t *testing.T) {
+	serveResults(t, map[string]string{
+		"/pairs": `[
+			{"symbol":"btcusd","id":9},
+			{"symbol":"ethbtc","id":45},
+			{"symbol":"btcusd","id":4}
+		]`,
+	})
+
+	ids, symbols, err := PairIDMap()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, symbol := range []string{"btcusd", "ethbtc"} {
+		if got := symbols[ids[symbol]]; got != symbol {
+			t.Errorf("symbol %q round-tripped to %q", symbol, got)
+		}
+	}
+	if ids["btcusd"] != 4 || symbols[9] != "btcusd" {
+		t.Errorf("duplicate symbol mapped to %d and %q", ids["btcusd"], symbols[9])
+	}
+}
+
+func TestFiatMarketsForAsset(t *testing.T) {
+	serveResults(t, map[string]string{
+		"/assets": `[
+			{"symbol":"btc","fiat":false},
+			{"symbol":"eth","fiat":false},
+			{"symbol":"usd","fiat":true},
+			{"symbol":"eur","fiat":true},
+			{"symbol":"jpy","fiat":true}
+		]`,
+		"/pairs": `[
+			{"symbol":"btcusd","base":{"symbol":"btc"},"quote":{"symbol":"usd"}},
+			{"symbol":"btceur","base":{"symbol":"btc"},"quote":{"symbol":"eur"}},
+			{"symbol":"ethbtc","base":{"symbol":"eth"},"quote":{"symbol":"btc"}},
+			{"symbol":"ethusd","base":{"symbol":"eth"},"quote":{"symbol":"usd"}}
+		]`,
+		"/assets/eth": `{"symbol":"eth","markets":{
+			"base":[
+				{"exchange":"kraken","pair":"ethbtc","active":true},
+				{"exchange":"kraken","pair":"ethusd","active":true},
+				{"exchange":"gdax","pair":"ethusd","active":true}
+			],
+			"quote":[]
+		}}`,
+		"/assets/btc": `{"symbol":"btc","markets":{
+			"base":[
+				{"exchange":"kraken","pair":"btceur","active":true},
+				{"exchange":"kraken","pair":"btcusd","active":true}
+			],
+			"quote":[
+				{"exchange":"kraken","pair":"ethbtc","active":true}
+			]
+		}}`,
+	})
+
+	fiats, err := FiatMarketsForAsset(context.Background(), "eth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"usd"}; !reflect.DeepEqual(fiats, expected) {
+		t.Errorf("eth fiats = %v, want %v", fiats, expected)
+	}
+
+	fiats, err = FiatMarketsForAsset(context.Background(), "btc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"eur", "usd"}; !reflect.DeepEqual(fiats, expected) {
+		t.Errorf("btc fiats = %v, want %v", fiats, expected)
+	}
+}
+
+func TestMarketActive(t *testing.T) {
+	serveResults(t, map[string]string{
+		"/markets/kraken/btcusd": `{"exchange":"kraken","pair":"btcusd","active":true}`,
+		"/markets/quoine/btcusd": `{"exchange":"quoine","pair":"btcusd","active":false}`,
+	})
+
+	if active, err := MarketActive("kraken", "btcusd"); err != nil || !active {
+		t.Errorf("kraken btcusd = %v, %v, want active", active, err)
+	}
+	if active, err := MarketActive("quoine", "btcusd"); err != nil || active {
+		t.Errorf("quoine btcusd = %v, %v, want inactive", active, err)
+	}
+	if _, err := MarketActive("kraken", "dogeusd"); !errors.Is(err, ErrMarketNotFound) {
+		t.Errorf("err = %v, want ErrMarketNotFound", err)
+	}
+}
@@ -0,0 +1,170 @@
+package cryptowatch
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// WatchImbalance polls a market's order book every interval and emits the
+// imbalance of its top levels. Failed polls are skipped. The returned channel is
+// closed once ctx is done.
+func (c *Client) WatchImbalance(ctx context.Context, exchange, pair string, levels int, interval time.Duration) (<-chan float64, error) {
+	if levels <= 0 {
+		return nil, errors.New("levels must be positive")
+	}
+	if interval <= 0 {
+		return nil, errors.New("interval must be positive")
+	}
+
+	imbalances := make(chan float64)
+
+	go func() {
+		defer close(imbalances)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if book, err := c.OrderBookWithOptionsContext(ctx, exchange, pair, OrderBookOptions{Limit: levels}); err == nil {
+				select {
+				case imbalances <- book.Imbalance(levels):
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return imbalances, nil
+}
+
+// resilienceLevels is the number of levels per side measured by BookResilience.
+const resilienceLevels = 10
+
+// BookResilience samples a market's order book samples times, interval apart,
+// and measures how much of the depth lost whenever the book thins is
+// replenished by the following sample. Depth is the combined amount of the top
+// 10 levels of each side. The result is the average replenished fraction over
+// every thinning event: 1 means lost depth fully returned, 0 means none did and
+// values above 1 mean the book came back deeper. A book that never thins
+// returns 1. This is a coarse proxy: it cannot see changes between samples and
+// ignores where in the book depth was added.
+func (c *Client) BookResilience(ctx context.Context, exchange, pair string, samples int, interval time.Duration) (float64, error) {
+	if samples < 3 {
+		return 0, errors.New("at least 3 samples are required")
+	}
+	if interval <= 0 {
+		return 0, errors.New("interval must be positive")
+	}
+
+	depths := make([]float64, 0, samples)
+	for len(depths) < samples {
+		if len(depths) > 0 {
+			timer := time.NewTimer(interval)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return 0, ctx.Err()
+			}
+		}
+
+		book, err := c.OrderBookWithOptionsContext(ctx, exchange, pair, OrderBookOptions{Limit: resilienceLevels})
+
+		if err != nil {
+			return 0, err
+		}
+		depths = append(depths, sideVolume(book.sortedBids(), resilienceLevels)+sideVolume(book.sortedAsks(), resilienceLevels))
+	}
+
+	var recovered float64
+	events := 0
+	for i := 1; i < len(depths)-1; i++ {
+		if lost := depths[i-1] - depths[i]; lost > 0 {
+			recovered += (depths[i+1] - depths[i]) / lost
+			events++
+		}
+	}
+
+	if events == 0 {
+		return 1, nil
+	}
+	return recovered / float64(events), nil
+}
+
+// RollingVWAP polls a market's recent trades every interval and emits the
+// volume-weighted average price of the trades executed within the trailing
+// window. Trades are de-duplicated across polls and evicted once they fall out
+// of the window. Nothing is emitted while the window is empty or a poll fails.
+// The returned channel is closed once ctx is done.
+func (c *Client) RollingVWAP(ctx context.Context, exchange, pair string, window time.Duration, interval time.Duration) (<-chan float64, error) {
+	if window <= 0 {
+		return nil, errors.New("window must be positive")
+	}
+	if interval <= 0 {
+		return nil, errors.New("interval must be positive")
+	}
+
+	vwaps := make(chan float64)
+
+	go func() {
+		defer close(vwaps)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var recent [][4]float64
+		seen := make(map[[4]float64]bool)
+
+		for {
+			if trades, err := c.TradesContext(ctx, exchange, pair); err == nil {
+				cutoff := float64(time.Now().Add(-window).Unix())
+
+				for _, trade := range trades {
+					if len(trade) < 4 {
+						continue
+					}
+					key := [4]float64{trade[0], trade[1], trade[2], trade[3]}
+					if !seen[key] && key[1] >= cutoff {
+						seen[key] = true
+						recent = append(recent, key)
+					}
+				}
+
+				kept := recent[:0]
+				var notional, volume float64
+				for _, trade := range recent {
+					if trade[1] < cutoff {
+						delete(seen, trade)
+						continue
+					}
+					kept = append(kept, trade)
+					notional += trade[2] * trade[3]
+					volume += trade[3]
+				}
+				recent = kept
+
+				if volume > 0 {
+					select {
+					case vwaps <- notional / volume:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return vwaps, nil
+}
@@ -0,0 +1,95 @@
+package cryptowatch
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWatchImbalance(t *testing.T) {
+	serveResults(t, map[string]string{
+		"/markets/kraken/btcusd/orderbook": `{"asks":[[101,1]],"bids":[[100,3]]}`,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	imbalances, err := WatchImbalance(ctx, "kraken", "btcusd", 1, time.Millisecond)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if imbalance := <-imbalances; imbalance != 0.5 {
+			t.Errorf("imbalance = %v, want 0.5", imbalance)
+		}
+	}
+
+	cancel()
+	for range imbalances {
+	}
+
+	if _, err := WatchImbalance(context.Background(), "kraken", "btcusd", 0, time.Second); err == nil {
+		t.Error("expected an error for non-positive levels")
+	}
+}
+
+func TestBookResilience(t *testing.T) {
+	depths := []float64{10, 6, 9, 9, 4, 10}
+	sample := 0
+	serve(t, func(w http.ResponseWriter, r *http.Request) {
+		depth := depths[sample]
+		sample++
+		fmt.Fprintf(w, `{"result":{"asks":[[101,%v]],"bids":[[100,%v]]}}`, depth/2, depth/2)
+	})
+
+	resilience, err := BookResilience(context.Background(), "kraken", "btcusd", len(depths), time.Millisecond)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := (0.75 + 1.2) / 2
+	if math.Abs(resilience-expected) > 1e-9 {
+		t.Errorf("resilience = %v, want %v", resilience, expected)
+	}
+
+	if _, err := BookResilience(context.Background(), "kraken", "btcusd", 2, time.Millisecond); err == nil {
+		t.Error("expected an error for too few samples")
+	}
+}
+
+func TestRollingVWAP(t *testing.T) {
+	now := time.Now().Unix()
+	polls := []string{
+		fmt.Sprintf(`[[1,%d,90,5],[2,%d,100,1],[3,%d,110,1]]`, now-120, now-10, now-5),
+		fmt.Sprintf(`[[2,%d,100,1],[3,%d,110,1],[4,%d,130,2]]`, now-10, now-5, now-1),
+	}
+	poll := 0
+	serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if poll >= len(polls) {
+			poll = len(polls) - 1
+		}
+		fmt.Fprintf(w, `{"result":%s}`, polls[poll])
+		poll++
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	vwaps, err := RollingVWAP(ctx, "kraken", "btcusd", time.Minute, time.Millisecond)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []float64{105, 117.5, 117.5} {
+		if vwap := <-vwaps; math.Abs(vwap-expected) > 1e-9 {
+			t.Errorf("vwap = %v, want %v", vwap, expected)
+		}
+	}
+
+	cancel()
+	for range vwaps {
+	}
+}
@@ -0,0 +1,181 @@
+package cryptowatch
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OHLC periods, keyed by their length in seconds as the api expects them.
+const (
+	Period1m  = "60"
+	Period3m  = "180"
+	Period5m  = "300"
+	Period15m = "900"
+	Period30m = "1800"
+	Period1h  = "3600"
+	Period2h  = "7200"
+	Period4h  = "14400"
+	Period6h  = "21600"
+	Period12h = "43200"
+	Period1d  = "86400"
+	Period3d  = "259200"
+	Period1w  = "604800"
+)
+
+// values encodes the options as query parameters.
+func (options OhlcOptions) values() url.Values {
+	query := url.Values{}
+
+	if len(options.Periods) > 0 {
+		query.Set("periods", strings.Join(options.Periods, ","))
+	}
+	if options.Before != 0 {
+		query.Set("before", strconv.FormatInt(options.Before, 10))
+	}
+	if options.After != 0 {
+		query.Set("after", strconv.FormatInt(options.After, 10))
+	}
+	return query
+}
+
+// candleFields is the number of leading columns of an OHLC row that make up a
+// candle: close time, open, high, low, close and volume.
+const candleFields = 6
+
+// Candles parses the rows of one period, keyed by its length in seconds such as
+// "3600", into candles. It fails when the period is missing or a row is short.
+func (ohlc OHLC) Candles(period string) ([]Candle, error) {
+	rows, ok := ohlc[period]
+	if !ok {
+		return nil, fmt.Errorf("no candles for period %s", period)
+	}
+
+	candles := make([]Candle, 0, len(rows))
+	for i, row := range rows {
+		if len(row) < candleFields {
+			return nil, fmt.Errorf("candle %d of period %s has %d fields, want %d", i, period, len(row), candleFields)
+		}
+		candles = append(candles, Candle{
+			CloseTime: time.Unix(int64(row[0]), 0),
+			Open:      row[1],
+			High:      row[2],
+			Low:       row[3],
+			Close:     row[4],
+			Volume:    row[5],
+		})
+	}
+	return candles, nil
+}
+
+// WriteCSV writes the candles of one period to w as csv, with a header row of
+// closetime, open, high, low, close and volume. Close times are RFC3339 in UTC.
+// It fails like Candles when the period is missing or a row is short.
+func (ohlc OHLC) WriteCSV(w io.Writer, period string) error {
+	candles, err := ohlc.Candles(period)
+
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"closetime", "open", "high", "low", "close", "volume"})
+	for _, candle := range candles {
+		writer.Write([]string{
+			candle.CloseTime.UTC().Format(time.RFC3339),
+			formatFloat(candle.Open),
+			formatFloat(candle.High),
+			formatFloat(candle.Low),
+			formatFloat(candle.Close),
+			formatFloat(candle.Volume),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// OhlcPeriod returns a market's candles for a single period, such as Period1h,
+// requesting only that period from the api.
+func (c *Client) OhlcPeriod(exchange, pair, period string) ([]Candle, error) {
+	return c.OhlcPeriodContext(context.Background(), exchange, pair, period)
+}
+
+// OhlcPeriodContext is like OhlcPeriod but aborts the request when ctx is done.
+func (c *Client) OhlcPeriodContext(ctx context.Context, exchange, pair, period string) ([]Candle, error) {
+	ohlc, err := c.OhlcWithOptionsContext(ctx, exchange, pair, OhlcOptions{Periods: []string{period}})
+
+	if err != nil {
+		return nil, err
+	}
+	return ohlc.Candles(period)
+}
+
+// PriceChangeOver computes a market's price change over lookback from its OHLC
+// data. The lookback is measured back from the latest candle's close time using
+// the finest candle period whose history reaches that far, and from is the close
+// of the candle nearest to that point. The change is a fraction like
+// Summary.Price.Change.Percentage. The result is only as precise as the chosen
+// period: a 7 day lookback may resolve to hourly candles while a multi-year one
+// falls back to daily or weekly candles.
+func (c *Client) PriceChangeOver(ctx context.Context, exchange, pair string, lookback time.Duration) (pct float64, from, to float64, err error) {
+	if lookback <= 0 {
+		return 0, 0, 0, errors.New("lookback must be positive")
+	}
+	ohlc, err := c.OhlcContext(ctx, exchange, pair)
+
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	periods := make([]int, 0, len(ohlc))
+	for key := range ohlc {
+		if seconds, err := strconv.Atoi(key); err == nil {
+			periods = append(periods, seconds)
+		}
+	}
+	sort.Ints(periods)
+
+	for _, period := range periods {
+		var rows [][]float64
+		for _, row := range ohlc[strconv.Itoa(period)] {
+			if len(row) >= 5 {
+				rows = append(rows, row)
+			}
+		}
+
+		if len(rows) == 0 {
+			continue
+		}
+
+		latest := rows[len(rows)-1]
+		target := latest[0] - lookback.Seconds()
+
+		if rows[0][0] > target {
+			continue
+		}
+
+		nearest := rows[0]
+		for _, row := range rows {
+			if math.Abs(row[0]-target) < math.Abs(nearest[0]-target) {
+				nearest = row
+			}
+		}
+
+		if nearest[4] == 0 {
+			return 0, 0, 0, fmt.Errorf("zero close price %v before the latest candle", lookback)
+		}
+
+		from, to = nearest[4], latest[4]
+		return (to - from) / from, from, to, nil
+	}
+
+	return 0, 0, 0, fmt.Errorf("no candle period covers a lookback of %v", lookback)
+}
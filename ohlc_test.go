@@ -0,0 +1,144 @@
+package cryptowatch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestPriceChangeOver(t *testing.T) {
+	serveResults(t, map[string]string{
+		"/markets/kraken/btcusd/ohlc": `{
+			"3600":[
+				[1500007200,99,101,98,100,10],
+				[1500010800,100,106,99,105,12],
+				[1500014400,105,111,104,110,8]
+			],
+			"86400":[
+				[1499500800,75,82,74,80,100],
+				[1499587200,80,91,79,90,110],
+				[1499673600,90,101,89,100,90],
+				[1500019200,100,121,99,120,95]
+			]
+		}`,
+	})
+
+	cases := []struct {
+		lookback time.Duration
+		from, to float64
+	}{
+		{time.Hour, 105, 110},
+		{2 * time.Hour, 100, 110},
+		{6 * 24 * time.Hour, 80, 120},
+		{5 * 24 * time.Hour, 90, 120},
+		{3 * 24 * time.Hour, 100, 120},
+	}
+	for _, c := range cases {
+		pct, from, to, err := PriceChangeOver(context.Background(), "kraken", "btcusd", c.lookback)
+
+		if err != nil {
+			t.Errorf("PriceChangeOver(%v): %v", c.lookback, err)
+			continue
+		}
+		if from != c.from || to != c.to || math.Abs(pct-(c.to-c.from)/c.from) > 1e-9 {
+			t.Errorf("PriceChangeOver(%v) = %v, %v, %v, want from %v to %v", c.lookback, pct, from, to, c.from, c.to)
+		}
+	}
+
+	if _, _, _, err := PriceChangeOver(context.Background(), "kraken", "btcusd", 365*24*time.Hour); err == nil {
+		t.Error("expected an error for a lookback beyond the available history")
+	}
+}
+
+func TestCandles(t *testing.T) {
+	ohlc := OHLC{
+		"60":   {{1500000060, 1, 3, 0.5, 2, 10, 20}, {1500000120, 2, 4, 1, 3, 5, 15}},
+		"3600": {{1500003600, 1, 2, 0.5}},
+	}
+
+	candles, err := ohlc.Candles("60")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Candle{CloseTime: time.Unix(1500000120, 0), Open: 2, High: 4, Low: 1, Close: 3, Volume: 5}
+	if len(candles) != 2 || candles[1] != expected {
+		t.Errorf("Candles(\"60\") = %+v", candles)
+	}
+
+	if _, err := ohlc.Candles("3600"); err == nil {
+		t.Error("expected an error for a short row")
+	}
+	if _, err := ohlc.Candles("86400"); err == nil {
+		t.Error("expected an error for a missing period")
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	ohlc := OHLC{
+		"3600": {{1500004800, 100, 110, 95, 105.5, 12.25, 1290}, {1500008400, 105.5, 106, 101, 102, 3, 310}},
+		"60":   {{1500000060, 1, 2}},
+	}
+
+	var buf bytes.Buffer
+	if err := ohlc.WriteCSV(&buf, "3600"); err != nil {
+		t.Fatal(err)
+	}
+	expected := "closetime,open,high,low,close,volume\n" +
+		"2017-07-14T04:00:00Z,100,110,95,105.5,12.25\n" +
+		"2017-07-14T05:00:00Z,105.5,106,101,102,3\n"
+	if buf.String() != expected {
+		t.Errorf("WriteCSV() wrote %q, want %q", buf.String(), expected)
+	}
+
+	if err := ohlc.WriteCSV(&buf, "86400"); err == nil {
+		t.Error("expected an error for a missing period")
+	}
+	if err := ohlc.WriteCSV(&buf, "60"); err == nil {
+		t.Error("expected an error for a short row")
+	}
+}
+
+func TestPeriodConstants(t *testing.T) {
+	periods := map[string]time.Duration{
+		Period1m: time.Minute, Period3m: 3 * time.Minute, Period5m: 5 * time.Minute,
+		Period15m: 15 * time.Minute, Period30m: 30 * time.Minute, Period1h: time.Hour,
+		Period2h: 2 * time.Hour, Period4h: 4 * time.Hour, Period6h: 6 * time.Hour,
+		Period12h: 12 * time.Hour, Period1d: 24 * time.Hour, Period3d: 72 * time.Hour,
+		Period1w: 7 * 24 * time.Hour,
+	}
+	for period, length := range periods {
+		if expected := strconv.Itoa(int(length.Seconds())); period != expected {
+			t.Errorf("period %s, want %s seconds", period, expected)
+		}
+	}
+}
+
+func TestOhlcPeriod(t *testing.T) {
+	var queries []string
+	serve(t, func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("periods"))
+		fmt.Fprint(w, `{"result":{"3600":[[1500004800,100,110,95,105.5,12.25,1290]]}}`)
+	})
+
+	candles, err := OhlcPeriod("kraken", "btcusd", Period1h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Candle{{CloseTime: time.Unix(1500004800, 0), Open: 100, High: 110, Low: 95, Close: 105.5, Volume: 12.25}}
+	if !reflect.DeepEqual(candles, expected) {
+		t.Errorf("got %+v, want %+v", candles, expected)
+	}
+	if len(queries) != 1 || queries[0] != "3600" {
+		t.Errorf("periods requested %q, want only 3600", queries)
+	}
+
+	if _, err := OhlcPeriod("kraken", "btcusd", Period1d); err == nil {
+		t.Error("expected an error when the period is missing from the response")
+	}
+}
@@ -0,0 +1,326 @@
+package cryptowatch
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// values encodes the options as query parameters, rejecting negative values.
+func (options OrderBookOptions) values() (url.Values, error) {
+	query := url.Values{}
+
+	if options.Depth < 0 || options.Span < 0 || options.Limit < 0 {
+		return nil, errors.New("order book options must not be negative")
+	}
+	if options.Depth != 0 {
+		query.Set("depth", strconv.FormatFloat(options.Depth, 'f', -1, 64))
+	}
+	if options.Span != 0 {
+		query.Set("span", strconv.FormatFloat(options.Span, 'f', -1, 64))
+	}
+	if options.Limit != 0 {
+		query.Set("limit", strconv.Itoa(options.Limit))
+	}
+	return query, nil
+}
+
+// Imbalance returns the normalized difference between bid and ask volume over the
+// top levels of each side, ranging from -1 (all asks) to 1 (all bids). The top
+// levels are the best priced, whatever order the sides are in. Levels is clamped
+// to the depth of each side and an empty book has an imbalance of 0.
+func (book MarketOrderBook) Imbalance(levels int) float64 {
+	bids := sideVolume(book.sortedBids(), levels)
+	asks := sideVolume(book.sortedAsks(), levels)
+
+	if bids+asks == 0 {
+		return 0
+	}
+	return (bids - asks) / (bids + asks)
+}
+
+// NotionalImbalance is Imbalance weighted by quote value: it compares the
+// price * amount committed over the top levels of each side. Levels is clamped to
+// the depth of each side and an empty book has an imbalance of 0.
+func (book MarketOrderBook) NotionalImbalance(levels int) float64 {
+	bids := sideNotional(book.sortedBids(), levels)
+	asks := sideNotional(book.sortedAsks(), levels)
+
+	if bids+asks == 0 {
+		return 0
+	}
+	return (bids - asks) / (bids + asks)
+}
+
+// TypedAsks returns the asks of the book as entries, skipping malformed rows
+// with fewer than two elements.
+func (book MarketOrderBook) TypedAsks() []OrderBookEntry {
+	return typedSide(book.Asks)
+}
+
+// TypedBids returns the bids of the book as entries, skipping malformed rows
+// with fewer than two elements.
+func (book MarketOrderBook) TypedBids() []OrderBookEntry {
+	return typedSide(book.Bids)
+}
+
+// typedSide maps the raw levels of one side of a book to entries.
+func typedSide(side [][]float64) []OrderBookEntry {
+	entries := make([]OrderBookEntry, 0, len(side))
+
+	for _, level := range side {
+		if len(level) >= 2 {
+			entries = append(entries, OrderBookEntry{Price: level[0], Amount: level[1]})
+		}
+	}
+	return entries
+}
+
+// sortedBids returns the bids as entries, highest price first. The api already
+// sorts them so, but the order is not relied on.
+func (book MarketOrderBook) sortedBids() []OrderBookEntry {
+	levels := book.TypedBids()
+	sort.SliceStable(levels, func(i, j int) bool { return levels[i].Price > levels[j].Price })
+	return levels
+}
+
+// sortedAsks returns the asks as entries, lowest price first. The api already
+// sorts them so, but the order is not relied on.
+func (book MarketOrderBook) sortedAsks() []OrderBookEntry {
+	levels := book.TypedAsks()
+	sort.SliceStable(levels, func(i, j int) bool { return levels[i].Price < levels[j].Price })
+	return levels
+}
+
+// topLevels returns at most the first levels entries of a sorted side.
+func topLevels(side []OrderBookEntry, levels int) []OrderBookEntry {
+	if levels < 0 {
+		levels = 0
+	}
+	if levels < len(side) {
+		return side[:levels]
+	}
+	return side
+}
+
+// sideVolume sums the amount of the top levels of one sorted side of a book.
+func sideVolume(side []OrderBookEntry, levels int) float64 {
+	var volume float64
+
+	for _, level := range topLevels(side, levels) {
+		volume += level.Amount
+	}
+	return volume
+}
+
+// sideNotional sums the price * amount of the top levels of one sorted side of a
+// book.
+func sideNotional(side []OrderBookEntry, levels int) float64 {
+	var notional float64
+
+	for _, level := range topLevels(side, levels) {
+		notional += level.Price * level.Amount
+	}
+	return notional
+}
+
+// LiquidityWithin sums the amounts resting within pct of the mid price on each
+// side of the book, where pct is a fraction (0.01 is 1%). Both volumes are zero
+// when pct is not positive or the mid is unknown because a side is empty.
+func (book MarketOrderBook) LiquidityWithin(pct float64) (bidVol, askVol float64) {
+	mid, ok := book.MidPrice()
+
+	if pct <= 0 || !ok {
+		return 0, 0
+	}
+
+	floor, ceiling := mid*(1-pct), mid*(1+pct)
+
+	for _, level := range book.Bids {
+		if len(level) >= 2 && level[0] >= floor {
+			bidVol += level[1]
+		}
+	}
+	for _, level := range book.Asks {
+		if len(level) >= 2 && level[0] <= ceiling {
+			askVol += level[1]
+		}
+	}
+
+	return bidVol, askVol
+}
+
+// BestBid returns the highest bid. The api sorts bids descending so this is
+// normally the first, but the whole side is checked in case it is not. ok is
+// false when there are no bids.
+func (book MarketOrderBook) BestBid() (entry OrderBookEntry, ok bool) {
+	for _, level := range book.TypedBids() {
+		if !ok || level.Price > entry.Price {
+			entry, ok = level, true
+		}
+	}
+	return entry, ok
+}
+
+// BestAsk returns the lowest ask. The api sorts asks ascending so this is
+// normally the first, but the whole side is checked in case it is not. ok is
+// false when there are no asks.
+func (book MarketOrderBook) BestAsk() (entry OrderBookEntry, ok bool) {
+	for _, level := range book.TypedAsks() {
+		if !ok || level.Price < entry.Price {
+			entry, ok = level, true
+		}
+	}
+	return entry, ok
+}
+
+// Spread returns the best ask minus the best bid. ok is false when either side
+// is empty.
+func (book MarketOrderBook) Spread() (spread float64, ok bool) {
+	bid, ask, ok := book.topOfBook()
+
+	if !ok {
+		return 0, false
+	}
+	return ask - bid, true
+}
+
+// MidPrice returns the midpoint between the best bid and the best ask. ok is
+// false when either side is empty.
+func (book MarketOrderBook) MidPrice() (mid float64, ok bool) {
+	bid, bidOK := book.BestBid()
+	ask, askOK := book.BestAsk()
+
+	if !bidOK || !askOK {
+		return 0, false
+	}
+	return (bid.Price + ask.Price) / 2, true
+}
+
+// VWAP returns the average price of filling amount against one side of the
+// book, "asks" to buy or "bids" to sell, walking from the best price outwards.
+// filled is less than amount when the side is too thin, and both are zero when
+// it is empty.
+func (book MarketOrderBook) VWAP(side string, amount float64) (price, filled float64, err error) {
+	var levels []OrderBookEntry
+
+	switch side {
+	case "asks":
+		levels = book.sortedAsks()
+	case "bids":
+		levels = book.sortedBids()
+	default:
+		return 0, 0, fmt.Errorf("unknown order book side %q, want asks or bids", side)
+	}
+
+	if amount <= 0 {
+		return 0, 0, errors.New("amount must be positive")
+	}
+
+	var notional float64
+	for _, level := range levels {
+		if filled >= amount {
+			break
+		}
+		take := math.Min(level.Amount, amount-filled)
+		notional += take * level.Price
+		filled += take
+	}
+
+	if filled == 0 {
+		return 0, 0, nil
+	}
+	return notional / filled, filled, nil
+}
+
+// topOfBook returns the best bid and ask prices. ok is false when either side
+// is empty.
+func (book MarketOrderBook) topOfBook() (bid, ask float64, ok bool) {
+	bestBid, bidOK := book.BestBid()
+	bestAsk, askOK := book.BestAsk()
+
+	if !bidOK || !askOK {
+		return 0, 0, false
+	}
+	return bestBid.Price, bestAsk.Price, true
+}
+
+// DepthWeightedMid returns the average price of the top levels of both sides of
+// the book weighted by the amount resting at each level. The top levels are the
+// best priced, whatever order the sides are in. Levels is clamped to between 1
+// and the depth of each side. ok is false when either side is empty.
+func (book MarketOrderBook) DepthWeightedMid(levels int) (mid float64, ok bool) {
+	if _, _, ok := book.topOfBook(); !ok {
+		return 0, false
+	}
+	if levels < 1 {
+		levels = 1
+	}
+
+	var notional, volume float64
+	for _, side := range [][]OrderBookEntry{book.sortedBids(), book.sortedAsks()} {
+		notional += sideNotional(side, levels)
+		volume += sideVolume(side, levels)
+	}
+
+	if volume == 0 {
+		return 0, false
+	}
+	return notional / volume, true
+}
+
+// Depth returns the number of price levels on each side of the book. This is the
+// depth the api returned, not necessarily the full depth of the market.
+func (book MarketOrderBook) Depth() (bidLevels, askLevels int) {
+	return len(book.Bids), len(book.Asks)
+}
+
+// fingerprintPrecision is the number of decimals levels are rounded to before
+// fingerprinting.
+const fingerprintPrecision = 8
+
+// Fingerprint returns a stable hash of the book's levels. Levels are rounded to
+// 8 decimals and sorted, bids descending and asks ascending, so books with the
+// same levels share a fingerprint however their slices were ordered.
+func (book MarketOrderBook) Fingerprint() string {
+	hash := sha256.New()
+
+	for _, side := range []struct {
+		name       string
+		levels     [][]float64
+		descending bool
+	}{
+		{"bids", book.Bids, true},
+		{"asks", book.Asks, false},
+	} {
+		rows := make([]string, 0, len(side.levels))
+		prices := make(map[string]float64, len(side.levels))
+		for _, level := range side.levels {
+			if len(level) < 2 {
+				continue
+			}
+			price := strconv.FormatFloat(level[0], 'f', fingerprintPrecision, 64)
+			row := price + " " + strconv.FormatFloat(level[1], 'f', fingerprintPrecision, 64)
+			rows = append(rows, row)
+			// sort on the rounded price, so levels that round alike order alike
+			prices[row], _ = strconv.ParseFloat(price, 64)
+		}
+
+		sort.Slice(rows, func(i, j int) bool {
+			if prices[rows[i]] != prices[rows[j]] {
+				return (prices[rows[i]] > prices[rows[j]]) == side.descending
+			}
+			return rows[i] < rows[j]
+		})
+
+		fmt.Fprintf(hash, "%s\n%s\n", side.name, strings.Join(rows, "\n"))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,291 @@
+package cryptowatch
+
+import (
+	"math"
+	"testing"
+)
+
+func TestImbalance(t *testing.T) {
+	book := MarketOrderBook{
+		Asks: [][]float64{{101, 1}, {102, 2}, {103, 5}},
+		Bids: [][]float64{{100, 3}, {99, 1}},
+	}
+
+	cases := []struct {
+		levels   int
+		expected float64
+	}{
+		{1, 0.5},
+		{2, 1.0 / 7},
+		{10, -4.0 / 12},
+		{0, 0},
+	}
+	for _, c := range cases {
+		if got := book.Imbalance(c.levels); math.Abs(got-c.expected) > 1e-9 {
+			t.Errorf("Imbalance(%d) = %v, want %v", c.levels, got, c.expected)
+		}
+	}
+
+	if got := (MarketOrderBook{}).Imbalance(5); got != 0 {
+		t.Errorf("empty book imbalance = %v, want 0", got)
+	}
+}
+
+func TestLiquidityWithin(t *testing.T) {
+	book := MarketOrderBook{
+		Asks: [][]float64{{101, 1}, {101.5, 2}, {103, 5}},
+		Bids: [][]float64{{99, 3}, {98.5, 1}, {97, 4}},
+	}
+
+	bidVol, askVol := book.LiquidityWithin(0.02)
+	if bidVol != 4 || askVol != 3 {
+		t.Errorf("LiquidityWithin(0.02) = %v, %v, want 4, 3", bidVol, askVol)
+	}
+
+	bidVol, askVol = book.LiquidityWithin(0.005)
+	if bidVol != 0 || askVol != 0 {
+		t.Errorf("LiquidityWithin(0.005) = %v, %v, want 0, 0", bidVol, askVol)
+	}
+
+	bidVol, askVol = book.LiquidityWithin(0)
+	if bidVol != 0 || askVol != 0 {
+		t.Errorf("LiquidityWithin(0) = %v, %v, want 0, 0", bidVol, askVol)
+	}
+
+	bidVol, askVol = MarketOrderBook{Bids: book.Bids}.LiquidityWithin(0.02)
+	if bidVol != 0 || askVol != 0 {
+		t.Errorf("one-sided LiquidityWithin = %v, %v, want 0, 0", bidVol, askVol)
+	}
+}
+
+func TestDepthWeightedMid(t *testing.T) {
+	book := MarketOrderBook{
+		Asks: [][]float64{{101, 1}, {102, 3}},
+		Bids: [][]float64{{100, 2}, {99, 2}, {98, 10}},
+	}
+
+	cases := []struct {
+		levels   int
+		expected float64
+	}{
+		{1, (100*2 + 101*1) / 3.0},
+		{0, (100*2 + 101*1) / 3.0},
+		{2, (100*2 + 99*2 + 101*1 + 102*3) / 8.0},
+		{5, (100*2 + 99*2 + 98*10 + 101*1 + 102*3) / 18.0},
+	}
+	for _, c := range cases {
+		mid, ok := book.DepthWeightedMid(c.levels)
+		if !ok || math.Abs(mid-c.expected) > 1e-9 {
+			t.Errorf("DepthWeightedMid(%d) = %v, %v, want %v, true", c.levels, mid, ok, c.expected)
+		}
+	}
+
+	if _, ok := (MarketOrderBook{Bids: book.Bids}).DepthWeightedMid(2); ok {
+		t.Error("DepthWeightedMid of a one-sided book should not be ok")
+	}
+}
+
+func TestDepth(t *testing.T) {
+	book := MarketOrderBook{
+		Asks: [][]float64{{101, 1}},
+		Bids: [][]float64{{100, 3}, {99, 1}, {98, 2}},
+	}
+
+	if bids, asks := book.Depth(); bids != 3 || asks != 1 {
+		t.Errorf("Depth() = %d, %d, want 3, 1", bids, asks)
+	}
+	if bids, asks := (MarketOrderBook{}).Depth(); bids != 0 || asks != 0 {
+		t.Errorf("empty Depth() = %d, %d, want 0, 0", bids, asks)
+	}
+}
+
+func TestNotionalImbalance(t *testing.T) {
+	book := MarketOrderBook{
+		Asks: [][]float64{{30, 1}, {40, 1}},
+		Bids: [][]float64{{10, 1}, {5, 1}},
+	}
+
+	if got := book.Imbalance(2); got != 0 {
+		t.Errorf("Imbalance(2) = %v, want 0", got)
+	}
+
+	expected := (15.0 - 70) / (15 + 70)
+	if got := book.NotionalImbalance(2); math.Abs(got-expected) > 1e-9 {
+		t.Errorf("NotionalImbalance(2) = %v, want %v", got, expected)
+	}
+
+	expected = (10.0 - 30) / (10 + 30)
+	if got := book.NotionalImbalance(1); math.Abs(got-expected) > 1e-9 {
+		t.Errorf("NotionalImbalance(1) = %v, want %v", got, expected)
+	}
+
+	if got := (MarketOrderBook{}).NotionalImbalance(3); got != 0 {
+		t.Errorf("empty book NotionalImbalance = %v, want 0", got)
+	}
+
+	// the top levels are the best priced, not the first listed
+	unsorted := MarketOrderBook{
+		Asks: [][]float64{{40, 1}, {30, 1}},
+		Bids: [][]float64{{5, 1}, {10, 1}},
+	}
+	if got, want := unsorted.NotionalImbalance(1), book.NotionalImbalance(1); got != want {
+		t.Errorf("unsorted NotionalImbalance(1) = %v, want %v", got, want)
+	}
+	if got, want := unsorted.Imbalance(1), book.Imbalance(1); got != want {
+		t.Errorf("unsorted Imbalance(1) = %v, want %v", got, want)
+	}
+	if got, _ := unsorted.DepthWeightedMid(1); got != 20 {
+		t.Errorf("unsorted DepthWeightedMid(1) = %v, want 20", got)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	book := MarketOrderBook{
+		Asks: [][]float64{{101, 1}, {102, 2}},
+		Bids: [][]float64{{100, 3}, {99, 1}},
+	}
+	shuffled := MarketOrderBook{
+		Asks: [][]float64{{102, 2}, {101, 1}},
+		Bids: [][]float64{{99, 1}, {100.000000000001, 3}},
+	}
+
+	if book.Fingerprint() != shuffled.Fingerprint() {
+		t.Error("books with the same levels have different fingerprints")
+	}
+	if book.Fingerprint() != book.Fingerprint() {
+		t.Error("fingerprint is not stable")
+	}
+
+	changed := []MarketOrderBook{
+		{Asks: [][]float64{{101, 1}, {102, 2.5}}, Bids: book.Bids},
+		{Asks: book.Asks, Bids: [][]float64{{100, 3}}},
+		{Asks: book.Bids, Bids: book.Asks},
+	}
+	for _, other := range changed {
+		if other.Fingerprint() == book.Fingerprint() {
+			t.Errorf("book %v shares the fingerprint of %v", other, book)
+		}
+	}
+
+	// the levels differ only past the 8th decimal, which reverses their order
+	// before rounding
+	rounded := MarketOrderBook{Bids: [][]float64{{100.000000001, 1}, {100.000000002, 2}}}
+	reordered := MarketOrderBook{Bids: [][]float64{{100.000000002, 1}, {100.000000001, 2}}}
+	if rounded.Fingerprint() != reordered.Fingerprint() {
+		t.Error("books that round to the same levels have different fingerprints")
+	}
+}
+
+func TestTypedSides(t *testing.T) {
+	book := MarketOrderBook{
+		Asks: [][]float64{{101, 1}, {102}, {103, 5}},
+		Bids: [][]float64{{100, 3}, {}},
+	}
+
+	asks := book.TypedAsks()
+	if len(asks) != 2 || asks[0] != (OrderBookEntry{101, 1}) || asks[1] != (OrderBookEntry{103, 5}) {
+		t.Errorf("TypedAsks() = %+v", asks)
+	}
+	bids := book.TypedBids()
+	if len(bids) != 1 || bids[0] != (OrderBookEntry{Price: 100, Amount: 3}) {
+		t.Errorf("TypedBids() = %+v", bids)
+	}
+	if got := (MarketOrderBook{}).TypedBids(); len(got) != 0 {
+		t.Errorf("empty book TypedBids() = %+v", got)
+	}
+}
+
+func TestBestBidAsk(t *testing.T) {
+	book := MarketOrderBook{
+		Asks: [][]float64{{101, 1}, {102, 2}},
+		Bids: [][]float64{{99, 4}, {100, 3}},
+	}
+
+	if bid, ok := book.BestBid(); !ok || bid != (OrderBookEntry{Price: 100, Amount: 3}) {
+		t.Errorf("BestBid() = %+v, %v, want the unsorted 100 bid", bid, ok)
+	}
+	if ask, ok := book.BestAsk(); !ok || ask != (OrderBookEntry{Price: 101, Amount: 1}) {
+		t.Errorf("BestAsk() = %+v, %v, want the 101 ask", ask, ok)
+	}
+	if spread, ok := book.Spread(); !ok || spread != 1 {
+		t.Errorf("Spread() = %v, %v, want 1", spread, ok)
+	}
+
+	oneSided := MarketOrderBook{Asks: book.Asks}
+	if _, ok := oneSided.BestBid(); ok {
+		t.Error("one-sided BestBid() ok, want no bid")
+	}
+	if ask, ok := oneSided.BestAsk(); !ok || ask.Price != 101 {
+		t.Errorf("one-sided BestAsk() = %+v, %v, want 101", ask, ok)
+	}
+	if _, ok := oneSided.Spread(); ok {
+		t.Error("one-sided Spread() ok, want no spread")
+	}
+
+	var empty MarketOrderBook
+	if _, ok := empty.BestBid(); ok {
+		t.Error("empty BestBid() ok")
+	}
+	if _, ok := empty.BestAsk(); ok {
+		t.Error("empty BestAsk() ok")
+	}
+	if _, ok := empty.Spread(); ok {
+		t.Error("empty Spread() ok")
+	}
+}
+
+func TestVWAP(t *testing.T) {
+	book := MarketOrderBook{
+		Asks: [][]float64{{101, 1}, {102, 2}, {104, 1}},
+		Bids: [][]float64{{100, 2}, {99, 3}},
+	}
+
+	cases := []struct {
+		side          string
+		amount        float64
+		price, filled float64
+	}{
+		{"asks", 0.5, 101, 0.5},
+		{"asks", 3, (101 + 2*102) / 3.0, 3},
+		{"asks", 2, (101 + 102) / 2.0, 2},
+		{"asks", 10, (101 + 2*102 + 104) / 4.0, 4},
+		{"bids", 2, 100, 2},
+		{"bids", 4, (2*100 + 2*99) / 4.0, 4},
+	}
+	for _, c := range cases {
+		price, filled, err := book.VWAP(c.side, c.amount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(price-c.price) > 1e-9 || filled != c.filled {
+			t.Errorf("VWAP(%q, %v) = %v, %v, want %v, %v", c.side, c.amount, price, filled, c.price, c.filled)
+		}
+	}
+
+	if _, _, err := book.VWAP("buy", 1); err == nil {
+		t.Error("expected an error for an unknown side")
+	}
+	if _, _, err := book.VWAP("asks", 0); err == nil {
+		t.Error("expected an error for a zero amount")
+	}
+	if price, filled, err := (MarketOrderBook{}).VWAP("bids", 1); err != nil || price != 0 || filled != 0 {
+		t.Errorf("empty VWAP = %v, %v, %v, want 0, 0, nil", price, filled, err)
+	}
+}
+
+func TestMidPrice(t *testing.T) {
+	book := MarketOrderBook{
+		Asks: [][]float64{{101, 1}, {102, 2}},
+		Bids: [][]float64{{100, 3}},
+	}
+
+	if mid, ok := book.MidPrice(); !ok || mid != 100.5 {
+		t.Errorf("MidPrice() = %v, %v, want 100.5", mid, ok)
+	}
+	if _, ok := (MarketOrderBook{Bids: book.Bids}).MidPrice(); ok {
+		t.Error("one-sided MidPrice() ok, want no mid")
+	}
+	if _, ok := (MarketOrderBook{}).MidPrice(); ok {
+		t.Error("empty MidPrice() ok, want no mid")
+	}
+}
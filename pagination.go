@@ -0,0 +1,99 @@
+package cryptowatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// pageQuery encodes a page request as query parameters. An empty cursor and a
+// zero limit are omitted.
+func pageQuery(cursor string, limit int) (url.Values, error) {
+	query := url.Values{}
+
+	if limit < 0 {
+		return nil, errors.New("page limit must not be negative")
+	}
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	if limit != 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	return query, nil
+}
+
+// followCursor calls fetch with each page's cursor, starting from the first
+// page, until there are no more pages or fetch fails. It stops with an error if
+// the api hands back a cursor it has already followed, which would loop forever.
+func followCursor(fetch func(cursor string) (next string, err error)) error {
+	seen := make(map[string]bool)
+	cursor := ""
+
+	for {
+		next, err := fetch(cursor)
+
+		if err != nil || next == "" {
+			return err
+		}
+		if seen[next] {
+			return fmt.Errorf("pagination cursor %q repeated", next)
+		}
+		seen[next] = true
+		cursor = next
+	}
+}
+
+// AllAssets returns every asset, following the cursor through each page. On
+// error it returns the assets collected so far along with the error.
+func (c *Client) AllAssets(ctx context.Context) ([]Asset, error) {
+	var assets []Asset
+
+	err := followCursor(func(cursor string) (string, error) {
+		page, next, err := c.AssetsPageContext(ctx, cursor, 0)
+		assets = append(assets, page...)
+		return next, err
+	})
+	return assets, err
+}
+
+// AllPairs returns every pair, following the cursor through each page. On
+// error it returns the pairs collected so far along with the error.
+func (c *Client) AllPairs(ctx context.Context) ([]Pair, error) {
+	var pairs []Pair
+
+	err := followCursor(func(cursor string) (string, error) {
+		page, next, err := c.PairsPageContext(ctx, cursor, 0)
+		pairs = append(pairs, page...)
+		return next, err
+	})
+	return pairs, err
+}
+
+// AllExchanges returns every exchange, following the cursor through each page. On
+// error it returns the exchanges collected so far along with the error.
+func (c *Client) AllExchanges(ctx context.Context) ([]GeneralExchange, error) {
+	var exchanges []GeneralExchange
+
+	err := followCursor(func(cursor string) (string, error) {
+		page, next, err := c.ExchangesPageContext(ctx, cursor, 0)
+		exchanges = append(exchanges, page...)
+		return next, err
+	})
+	return exchanges, err
+}
+
+// AllMarkets returns every market, following the cursor through each page. On
+// error it returns the markets collected so far along with the error.
+func (c *Client) AllMarkets(ctx context.Context) ([]GeneralMarket, error) {
+	var markets []GeneralMarket
+
+	err := followCursor(func(cursor string) (string, error) {
+		page, next, err := c.MarketsPageContext(ctx, cursor, 0)
+		markets = append(markets, page...)
+		return next, err
+	})
+	return markets, err
+}
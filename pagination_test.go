@@ -0,0 +1,76 @@
+package cryptowatch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestMarketsPage(t *testing.T) {
+	var queries []string
+	serve(t, func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			fmt.Fprint(w, `{"result":[{"exchange":"kraken","pair":"btcusd","active":true}],"cursor":{"last":"abc","hasMore":true}}`)
+		case "abc":
+			fmt.Fprint(w, `{"result":[{"exchange":"gdax","pair":"btcusd","active":true}],"cursor":{"last":"def","hasMore":false}}`)
+		}
+	})
+
+	markets, next, err := MarketsPage("", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(markets) != 1 || markets[0].Exchange != "kraken" || next != "abc" {
+		t.Errorf("first page = %+v, next %q", markets, next)
+	}
+
+	markets, next, err = MarketsPage(next, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(markets) != 1 || markets[0].Exchange != "gdax" || next != "" {
+		t.Errorf("last page = %+v, next %q", markets, next)
+	}
+
+	if expected := []string{"limit=1", "cursor=abc&limit=1"}; fmt.Sprint(queries) != fmt.Sprint(expected) {
+		t.Errorf("queries = %q, want %q", queries, expected)
+	}
+
+	if _, _, err := MarketsPage("", -1); err == nil {
+		t.Error("expected an error for a negative limit")
+	}
+}
+
+func TestAllMarkets(t *testing.T) {
+	pages := map[string]string{
+		"":    `{"result":[{"exchange":"kraken","pair":"btcusd"}],"cursor":{"last":"abc","hasMore":true}}`,
+		"abc": `{"result":[{"exchange":"gdax","pair":"btcusd"}],"cursor":{"last":"def","hasMore":true}}`,
+		"def": `{"result":[{"exchange":"bitstamp","pair":"btcusd"}],"cursor":{"last":"ghi","hasMore":false}}`,
+	}
+	serve(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, pages[r.URL.Query().Get("cursor")])
+	})
+
+	markets, err := AllMarkets(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(markets) != 3 || markets[2].Exchange != "bitstamp" {
+		t.Errorf("AllMarkets() = %+v, want all 3 pages", markets)
+	}
+
+	pages["def"] = `{"result":[{"exchange":"bitstamp","pair":"btcusd"}],"cursor":{"last":"abc","hasMore":true}}`
+	markets, err = AllMarkets(context.Background())
+	if err == nil || len(markets) != 3 {
+		t.Errorf("got %d markets and err %v, want the 3 collected and a repeated cursor error", len(markets), err)
+	}
+
+	pages["abc"] = `{"error":"Route not found"}`
+	markets, err = AllMarkets(context.Background())
+	if err == nil || len(markets) != 1 {
+		t.Errorf("got %d markets and err %v, want the first page and an error", len(markets), err)
+	}
+}
@@ -0,0 +1,56 @@
+package cryptowatch
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// limiter is a token bucket holding up to burst tokens that refills at rate
+// tokens per second.
+type limiter struct {
+	rate  float64
+	burst int
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// newLimiter returns a limiter with a full bucket.
+func newLimiter(rate float64, burst int) *limiter {
+	return &limiter{rate: rate, burst: burst, tokens: float64(burst), last: time.Now()}
+}
+
+// wait takes a token, blocking until one is available or ctx is done. A token
+// is reserved up front, so concurrent callers queue rather than race, and is
+// handed back if ctx ends before it is used.
+func (l *limiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > float64(l.burst) {
+		l.tokens = float64(l.burst)
+	}
+	l.last = now
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
@@ -1,24 +1,27 @@
 package cryptowatch
 
+import "time"
+
 const base = "https://api.cryptowat.ch/"
 
-// cryptowatch indexes
+// cryptowatch indexes, relative to a Client's base url
 var indexes = map[string]string{
-	"Assets":              base + "assets",
-	"Asset":               base + "assets/%v",
-	"Pairs":               base + "pairs",
-	"Pair":                base + "pairs/%v",
-	"Exchanges":           base + "exchanges",
-	"Exchange":            base + "exchanges/%v",
-	"Markets":             base + "markets",
-	"Market":              base + "markets/%v/%v",
-	"MarketPrice":         base + "markets/%v/%v/price",
-	"MarketSummary":       base + "markets/%v/%v/summary",
-	"MarketTrades":        base + "markets/%v/%v/trades",
-	"MarketOrderBook":     base + "markets/%v/%v/orderbook",
-	"MarketOHLC":          base + "markets/%v/%v/ohlc",
-	"AggregratePrices":    base + "markets/prices",
-	"AggregrateSummaries": base + "markets/summaries",
+	"Assets":              "assets",
+	"Asset":               "assets/%v",
+	"Pairs":               "pairs",
+	"Pair":                "pairs/%v",
+	"Exchanges":           "exchanges",
+	"Exchange":            "exchanges/%v",
+	"ExchangeMarkets":     "markets/%v",
+	"Markets":             "markets",
+	"Market":              "markets/%v/%v",
+	"MarketPrice":         "markets/%v/%v/price",
+	"MarketSummary":       "markets/%v/%v/summary",
+	"MarketTrades":        "markets/%v/%v/trades",
+	"MarketOrderBook":     "markets/%v/%v/orderbook",
+	"MarketOHLC":          "markets/%v/%v/ohlc",
+	"AggregratePrices":    "markets/prices",
+	"AggregrateSummaries": "markets/summaries",
 }
 
 // AssetMarket contains details for a quote or base for a market
@@ -39,6 +42,7 @@ type PairData struct {
 
 // Asset holds the general data for a cryptowatch asset
 type Asset struct {
+	ID     int    `json:"id,omitempty"`
 	Symbol string `json:"symbol"`
 	Name   string `json:"name"`
 	Fiat   bool   `json:"fiat"`
@@ -127,23 +131,131 @@ type Summary struct {
 			Absolute   float64 `json:"absolute"`
 		} `json:"change"`
 	} `json:"price"`
-	Volume float64 `json:"volume"`
+	Volume      float64  `json:"volume"`
+	VolumeQuote *float64 `json:"volumeQuote,omitempty"`
 }
 
 // Trade contains trading information for an asset
 type Trade []float64
 
+// TradeOptions limits the number of trades, up to MaxTradesLimit, and selects
+// the trades since a unix time in seconds. Zero values are omitted.
+type TradeOptions struct {
+	Limit int
+	Since int64
+}
+
 // MarketOrderBook contains the ask/bid prices for a market
 type MarketOrderBook struct {
 	Asks [][]float64 `json:"asks"`
 	Bids [][]float64 `json:"bids"`
 }
 
+// OrderBookEntry is a single price level of an order book
+type OrderBookEntry struct {
+	Price  float64
+	Amount float64
+}
+
+// OrderBookOptions truncates and aggregates an order book request. Depth limits
+// each side to the levels within that cumulative amount, Span to the levels
+// within that percentage of the mid, and Limit to that number of levels. Zero
+// values are omitted.
+type OrderBookOptions struct {
+	Depth float64
+	Span  float64
+	Limit int
+}
+
 // OHLC contains open-high-low-close info for a market
 type OHLC map[string][][]float64
 
+// OhlcOptions selects the candle periods, given in seconds, and the close time
+// range, given in unix seconds, of an OHLC request. Zero values are omitted.
+type OhlcOptions struct {
+	Periods []string
+	Before  int64
+	After   int64
+}
+
+// Candle is a single OHLC row with named fields
+type Candle struct {
+	CloseTime time.Time
+	Open      float64
+	High      float64
+	Low       float64
+	Close     float64
+	Volume    float64
+}
+
 // AggregratePrice contains prices on all markets
 type AggregratePrice map[string]float64
 
 // AggregrateSummary contains summary for all markets
 type AggregrateSummary map[string]Summary
+
+// Mover is a market and its 24-hour price change as a fraction
+type Mover struct {
+	Market    string
+	ChangePct float64
+}
+
+// ExchangeRank contains the number of active markets listed on an exchange
+type ExchangeRank struct {
+	Exchange      string
+	ActiveMarkets int
+}
+
+// GeneralMarkets is a listing of markets as returned by Markets
+type GeneralMarkets []GeneralMarket
+
+// MarketRef identifies a single market by exchange and pair
+type MarketRef struct {
+	Exchange string
+	Pair     string
+}
+
+// MarketSpread contains the top of book spread for a market
+type MarketSpread struct {
+	Market    MarketRef
+	Bid       float64
+	Ask       float64
+	SpreadBps float64
+}
+
+// MarketLiquidity contains the amount resting near the mid on each side of a market
+type MarketLiquidity struct {
+	Market    MarketRef
+	BidVolume float64
+	AskVolume float64
+}
+
+// Total returns the combined bid and ask volume
+func (liquidity MarketLiquidity) Total() float64 {
+	return liquidity.BidVolume + liquidity.AskVolume
+}
+
+// PriceOutlier contains a market price that deviates from the median across markets
+type PriceOutlier struct {
+	Market    MarketRef
+	Price     float64
+	Median    float64
+	Deviation float64
+}
+
+// MarketFilter selects markets by exchange, base and quote asset symbols. Empty
+// fields match every market.
+type MarketFilter struct {
+	Exchange   string
+	Base       string
+	Quote      string
+	ActiveOnly bool
+}
+
+// Allowance contains the request budget reported with each api response
+type Allowance struct {
+	Cost          float64 `json:"cost"`
+	Remaining     float64 `json:"remaining"`
+	RemainingPaid float64 `json:"remainingPaid"`
+	Upgrade       string  `json:"upgrade,omitempty"`
+}
@@ -0,0 +1,52 @@
+package cryptowatch
+
+import (
+	"math/rand"
+	"time"
+)
+
+// maxRetryDelay caps the backoff between retries and the Retry-After wait
+// retries honour.
+const maxRetryDelay = 30 * time.Second
+
+// retryPolicy configures how a Client retries failed requests.
+type retryPolicy struct {
+	maxAttempts int
+	baseDelay   time.Duration
+}
+
+// WithRetry retries requests that fail because the connection dropped, the api
+// throttled them (429) or the server errored (5xx), making up to maxAttempts
+// attempts in all. Retries back off exponentially from baseDelay with jitter,
+// or wait as long as a 429's Retry-After header asks. A 429 asking for a wait
+// longer than the 30 second backoff cap, and other failures such as a 404, are
+// returned straight away. A maxAttempts below 2 disables retries.
+//
+// Once retries are enabled, failures are returned as a *RetryError recording
+// the number of attempts made.
+func WithRetry(maxAttempts int, baseDelay time.Duration) Option {
+	return func(c *Client) {
+		if maxAttempts > 1 {
+			c.retry = &retryPolicy{maxAttempts: maxAttempts, baseDelay: baseDelay}
+		} else {
+			c.retry = nil
+		}
+	}
+}
+
+// backoff returns the delay before the retry following the given attempt: the
+// base delay doubled for each earlier attempt, capped at maxRetryDelay, with
+// the upper half picked at random so concurrent clients spread out.
+func (p *retryPolicy) backoff(attempt int) time.Duration {
+	delay := p.baseDelay
+	for i := 1; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
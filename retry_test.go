@@ -0,0 +1,98 @@
+package cryptowatch
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWithRetry(t *testing.T) {
+	statuses := map[string][]int{
+		"/markets/kraken/btcusd/price": {503, 429, 200},
+		"/markets/gdax/btcusd/price":   {500, 500, 500, 200},
+		"/markets/mtgox/btcusd/price":  {404},
+	}
+	requests := map[string]int{}
+	url := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		status := statuses[r.URL.Path][requests[r.URL.Path]]
+		requests[r.URL.Path]++
+
+		switch status {
+		case 200:
+			fmt.Fprint(w, `{"result":{"price":42}}`)
+		case 429:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(status)
+			fmt.Fprint(w, `{"error":"Too Many Requests"}`)
+		default:
+			w.WriteHeader(status)
+			fmt.Fprint(w, `{"error":"Something went wrong"}`)
+		}
+	})
+
+	c := NewClient(WithBaseURL(url), WithRetry(3, time.Millisecond))
+
+	price, err := c.MarketPrice("kraken", "btcusd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price != 42 || requests["/markets/kraken/btcusd/price"] != 3 {
+		t.Errorf("price = %v after %d requests, want 42 after 3", price, requests["/markets/kraken/btcusd/price"])
+	}
+
+	_, err = c.MarketPrice("gdax", "btcusd")
+	var retryErr *RetryError
+	var serverErr *ServerError
+	if !errors.As(err, &retryErr) || retryErr.Attempts != 3 || !errors.As(err, &serverErr) || serverErr.StatusCode != 500 {
+		t.Errorf("err = %v, want a *RetryError for a 500 after 3 attempts", err)
+	}
+
+	_, err = c.MarketPrice("mtgox", "btcusd")
+	if !errors.As(err, &retryErr) || retryErr.Attempts != 1 {
+		t.Errorf("err = %v, want a *RetryError after 1 attempt", err)
+	}
+	if requests["/markets/mtgox/btcusd/price"] != 1 {
+		t.Errorf("a 404 was requested %d times, want 1", requests["/markets/mtgox/btcusd/price"])
+	}
+}
+
+func TestWithRetryLongRetryAfter(t *testing.T) {
+	requests := 0
+	url := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"error":"Too Many Requests"}`)
+	})
+
+	c := NewClient(WithBaseURL(url), WithRetry(3, time.Millisecond))
+	_, err := c.MarketPrice("kraken", "btcusd")
+
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) || rateErr.RetryAfter != time.Hour {
+		t.Errorf("err = %v, want a RateLimitError asking for an hour", err)
+	}
+	if requests != 1 {
+		t.Errorf("requested %d times, want 1", requests)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := retryPolicy{maxAttempts: 10, baseDelay: 100 * time.Millisecond}
+
+	cases := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{1, 50 * time.Millisecond, 100 * time.Millisecond},
+		{3, 200 * time.Millisecond, 400 * time.Millisecond},
+		{20, maxRetryDelay / 2, maxRetryDelay},
+	}
+	for _, c := range cases {
+		if delay := policy.backoff(c.attempt); delay < c.min || delay > c.max {
+			t.Errorf("backoff(%d) = %v, want between %v and %v", c.attempt, delay, c.min, c.max)
+		}
+	}
+}
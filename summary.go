@@ -0,0 +1,136 @@
+package cryptowatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// QuoteVolume returns the 24-hour volume in the quote currency. It is the
+// VolumeQuote reported by the api when present and is otherwise approximated as
+// Volume * Price.Last, which uses the last price in place of the
+// volume-weighted average price over the window.
+func (summary Summary) QuoteVolume() float64 {
+	if summary.VolumeQuote != nil {
+		return *summary.VolumeQuote
+	}
+	return summary.Volume * summary.Price.Last
+}
+
+// String formats the summary for logs, e.g.
+// "last=41230.5 high=41800 low=40900 change=+1.24% (+505.3) vol=1823.4". The
+// percentage is shown to two decimals, and both changes take the sign of the
+// absolute change.
+func (summary Summary) String() string {
+	change := summary.Price.Change
+	sign := "+"
+
+	if change.Absolute < 0 {
+		sign = "-"
+	}
+
+	return fmt.Sprintf("last=%s high=%s low=%s change=%s%.2f%% (%s%s) vol=%s",
+		formatFloat(summary.Price.Last),
+		formatFloat(summary.Price.High),
+		formatFloat(summary.Price.Low),
+		sign, math.Abs(change.Percentage*100),
+		sign, formatFloat(math.Abs(change.Absolute)),
+		formatFloat(summary.Volume),
+	)
+}
+
+// formatFloat formats f in as few digits as represent it exactly.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// SummaryWithFallback tries the pair's summary on each preferred exchange in
+// order and returns the first that succeeds along with the exchange that
+// supplied it. It only fails when every exchange does, or ctx is done.
+func (c *Client) SummaryWithFallback(ctx context.Context, pair string, preferred []string) (Summary, string, error) {
+	if len(preferred) == 0 {
+		return Summary{}, "", errors.New("no exchanges to try")
+	}
+
+	failures := make([]string, 0, len(preferred))
+	for _, exchange := range preferred {
+		summary, err := c.MarketSummaryContext(ctx, exchange, pair)
+
+		if err == nil {
+			return summary, exchange, nil
+		}
+		if ctx.Err() != nil {
+			return Summary{}, "", ctx.Err()
+		}
+		failures = append(failures, exchange+": "+err.Error())
+	}
+
+	return Summary{}, "", fmt.Errorf("no summary for %s: %s", pair, strings.Join(failures, "; "))
+}
+
+// SummariesForExchange returns the 24-hour summaries of every market on
+// exchange. The api has no per-exchange summaries endpoint, so the aggregate
+// summaries are fetched and filtered here. The map keeps the aggregate's
+// "exchange:pair" keys, e.g. "kraken:btcusd". The exchange matches in any casing.
+func (c *Client) SummariesForExchange(exchange string) (map[string]Summary, error) {
+	return c.SummariesForExchangeContext(context.Background(), exchange)
+}
+
+// SummariesForExchangeContext is like SummariesForExchange but aborts the
+// request when ctx is done.
+func (c *Client) SummariesForExchangeContext(ctx context.Context, exchange string) (map[string]Summary, error) {
+	summaries, err := c.AggregrateSummariesContext(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := strings.ToLower(exchange) + ":"
+	filtered := make(map[string]Summary)
+	for key, summary := range summaries {
+		if strings.HasPrefix(strings.ToLower(key), prefix) {
+			filtered[key] = summary
+		}
+	}
+	return filtered, nil
+}
+
+// TopMovers returns the n markets with the largest 24-hour price change, keyed
+// as in the aggregate, e.g. "kraken:btcusd". Fewer are returned when there are
+// fewer than n markets. Ties are broken by market key.
+func (summaries AggregrateSummary) TopMovers(n int) []Mover {
+	return summaries.movers(n, true)
+}
+
+// BottomMovers returns the n markets with the smallest, i.e. most negative,
+// 24-hour price change, like TopMovers.
+func (summaries AggregrateSummary) BottomMovers(n int) []Mover {
+	return summaries.movers(n, false)
+}
+
+// movers sorts the summaries by price change and returns the first n.
+func (summaries AggregrateSummary) movers(n int, descending bool) []Mover {
+	movers := make([]Mover, 0, len(summaries))
+	for market, summary := range summaries {
+		movers = append(movers, Mover{Market: market, ChangePct: summary.Price.Change.Percentage})
+	}
+
+	sort.Slice(movers, func(i, j int) bool {
+		if movers[i].ChangePct != movers[j].ChangePct {
+			return (movers[i].ChangePct > movers[j].ChangePct) == descending
+		}
+		return movers[i].Market < movers[j].Market
+	})
+
+	if n < 0 {
+		n = 0
+	}
+	if n < len(movers) {
+		movers = movers[:n]
+	}
+	return movers
+}
@@ -0,0 +1,130 @@
+package cryptowatch
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestQuoteVolume(t *testing.T) {
+	var summary Summary
+	payload := `{"price":{"last":250.5,"high":260,"low":240,"change":{"percentage":0.02,"absolute":5}},"volume":1200}`
+
+	if err := json.Unmarshal([]byte(payload), &summary); err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.VolumeQuote != nil {
+		t.Errorf("VolumeQuote = %v, want nil", *summary.VolumeQuote)
+	}
+	if got := summary.QuoteVolume(); got != 300600 {
+		t.Errorf("QuoteVolume() = %v, want 300600", got)
+	}
+}
+
+func TestQuoteVolumeReported(t *testing.T) {
+	var summary Summary
+	payload := `{"price":{"last":250.5},"volume":1200,"volumeQuote":299000.25}`
+
+	if err := json.Unmarshal([]byte(payload), &summary); err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.VolumeQuote == nil || *summary.VolumeQuote != 299000.25 {
+		t.Fatalf("VolumeQuote = %v, want 299000.25", summary.VolumeQuote)
+	}
+	if got := summary.QuoteVolume(); got != 299000.25 {
+		t.Errorf("QuoteVolume() = %v, want 299000.25", got)
+	}
+}
+
+func TestSummaryWithFallback(t *testing.T) {
+	serveResults(t, map[string]string{
+		"/markets/gdax/btcusd/summary":   `{"price":{"last":101},"volume":7}`,
+		"/markets/kraken/btcusd/summary": `{"price":{"last":100},"volume":5}`,
+	})
+
+	summary, exchange, err := SummaryWithFallback(context.Background(), "btcusd", []string{"mtgox", "gdax", "kraken"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exchange != "gdax" || summary.Price.Last != 101 {
+		t.Errorf("got %+v from %q, want the gdax summary", summary, exchange)
+	}
+
+	if _, _, err := SummaryWithFallback(context.Background(), "btcusd", []string{"mtgox", "cryptsy"}); err == nil {
+		t.Error("expected an error when every exchange fails")
+	}
+}
+
+func TestSummariesForExchange(t *testing.T) {
+	serveResults(t, map[string]string{
+		"/markets/summaries": `{
+			"kraken:btcusd":{"price":{"last":100},"volume":5},
+			"kraken:ethusd":{"price":{"last":10},"volume":50},
+			"krakenfutures:btcusd":{"price":{"last":101},"volume":1},
+			"gdax:btcusd":{"price":{"last":99},"volume":7}
+		}`,
+	})
+
+	summaries, err := SummariesForExchange("Kraken")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summaries) != 2 || summaries["kraken:btcusd"].Price.Last != 100 || summaries["kraken:ethusd"].Volume != 50 {
+		t.Errorf("SummariesForExchange(\"Kraken\") = %+v, want the two kraken markets", summaries)
+	}
+}
+
+func TestSummaryString(t *testing.T) {
+	var up Summary
+	up.Price.Last, up.Price.High, up.Price.Low = 41230.5, 41800, 40900
+	up.Price.Change.Percentage, up.Price.Change.Absolute = 0.012409, 505.3
+	up.Volume = 1823.4
+
+	if got, expected := up.String(), "last=41230.5 high=41800 low=40900 change=+1.24% (+505.3) vol=1823.4"; got != expected {
+		t.Errorf("String() = %q, want %q", got, expected)
+	}
+
+	var down Summary
+	down.Price.Last, down.Price.High, down.Price.Low = 0.031, 0.0335, 0.0302
+	down.Price.Change.Percentage, down.Price.Change.Absolute = -0.0561, -0.00184
+	down.Volume = 20500
+
+	if got, expected := down.String(), "last=0.031 high=0.0335 low=0.0302 change=-5.61% (-0.00184) vol=20500"; got != expected {
+		t.Errorf("String() = %q, want %q", got, expected)
+	}
+}
+
+func TestMovers(t *testing.T) {
+	summaries := make(AggregrateSummary)
+	for market, change := range map[string]float64{
+		"kraken:btcusd":   0.05,
+		"gdax:btcusd":     0.05,
+		"kraken:ethusd":   -0.12,
+		"bitstamp:xrpusd": 0.31,
+		"gdax:ltcusd":     -0.02,
+	} {
+		var summary Summary
+		summary.Price.Change.Percentage = change
+		summaries[market] = summary
+	}
+
+	expected := []Mover{{"bitstamp:xrpusd", 0.31}, {"gdax:btcusd", 0.05}, {"kraken:btcusd", 0.05}}
+	if got := summaries.TopMovers(3); !reflect.DeepEqual(got, expected) {
+		t.Errorf("TopMovers(3) = %v, want %v", got, expected)
+	}
+
+	expected = []Mover{{"kraken:ethusd", -0.12}, {"gdax:ltcusd", -0.02}}
+	if got := summaries.BottomMovers(2); !reflect.DeepEqual(got, expected) {
+		t.Errorf("BottomMovers(2) = %v, want %v", got, expected)
+	}
+
+	if got := summaries.TopMovers(10); len(got) != 5 || got[4].Market != "kraken:ethusd" {
+		t.Errorf("TopMovers(10) = %v, want all 5 markets", got)
+	}
+	if got := summaries.BottomMovers(0); len(got) != 0 {
+		t.Errorf("BottomMovers(0) = %v, want none", got)
+	}
+}
@@ -0,0 +1,60 @@
+package cryptowatch
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// MaxTradesLimit is the largest number of trades the api returns in one request.
+const MaxTradesLimit = 1000
+
+// values encodes the options as query parameters, rejecting limits the api
+// would refuse.
+func (options TradeOptions) values() (url.Values, error) {
+	query := url.Values{}
+
+	if options.Limit < 0 || options.Limit > MaxTradesLimit {
+		return nil, fmt.Errorf("trades limit %d is outside 0 to %d", options.Limit, MaxTradesLimit)
+	}
+	if options.Limit != 0 {
+		query.Set("limit", strconv.Itoa(options.Limit))
+	}
+	if options.Since != 0 {
+		query.Set("since", strconv.FormatInt(options.Since, 10))
+	}
+	return query, nil
+}
+
+// field returns the element of the trade at i, or 0 when the row is too short.
+func (trade Trade) field(i int) float64 {
+	if i < len(trade) {
+		return trade[i]
+	}
+	return 0
+}
+
+// ID returns the trade's id, or 0 when the row is too short.
+func (trade Trade) ID() float64 {
+	return trade.field(0)
+}
+
+// Timestamp returns when the trade happened, or the zero time when the row is
+// too short.
+func (trade Trade) Timestamp() time.Time {
+	if len(trade) < 2 {
+		return time.Time{}
+	}
+	return time.Unix(int64(trade[1]), 0)
+}
+
+// Price returns the price the trade executed at, or 0 when the row is too short.
+func (trade Trade) Price() float64 {
+	return trade.field(2)
+}
+
+// Amount returns the amount traded, or 0 when the row is too short.
+func (trade Trade) Amount() float64 {
+	return trade.field(3)
+}
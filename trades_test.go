@@ -0,0 +1,28 @@
+package cryptowatch
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTradeAccessors(t *testing.T) {
+	trade := Trade{42, 1500000000, 101.5, 0.25}
+
+	if got := trade.ID(); got != 42 {
+		t.Errorf("ID() = %v, want 42", got)
+	}
+	if got := trade.Timestamp(); !got.Equal(time.Unix(1500000000, 0)) {
+		t.Errorf("Timestamp() = %v, want %v", got, time.Unix(1500000000, 0))
+	}
+	if got := trade.Price(); got != 101.5 {
+		t.Errorf("Price() = %v, want 101.5", got)
+	}
+	if got := trade.Amount(); got != 0.25 {
+		t.Errorf("Amount() = %v, want 0.25", got)
+	}
+
+	short := Trade{7}
+	if short.ID() != 7 || !short.Timestamp().IsZero() || short.Price() != 0 || short.Amount() != 0 {
+		t.Errorf("short trade accessors = %v %v %v %v", short.ID(), short.Timestamp(), short.Price(), short.Amount())
+	}
+}